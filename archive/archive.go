@@ -0,0 +1,241 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olympsis/go-mongo/driver"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Writer interface
+Receives the archived documents when they leave the database. ex: to cold storage
+*/
+type Writer interface {
+	Write(ctx context.Context, docs []bson.Raw) error
+}
+
+/*
+Options object
+Configures an archiver
+
+	Field: date field the age of the documents is computed from
+
+	MaxAge: documents older than this are archived
+
+	Filter: additional filter on the archived documents, can be nil
+
+	Archive: collection of the same database to move the documents to
+
+	Writer: writer to move the documents to when Archive is empty
+
+	BatchSize: number of documents moved per transaction, 500 when 0
+
+	Interval: time between two runs of Run, an hour when 0
+
+	Checkpoints: collection holding the progress of the archivers, "archive_checkpoints" when empty
+*/
+type Options struct {
+	Field       string
+	MaxAge      time.Duration
+	Filter      *driver.Filter
+	Archive     string
+	Writer      Writer
+	BatchSize   int64
+	Interval    time.Duration
+	Checkpoints string
+}
+
+/*
+Progress object
+Checkpoint of an archiver, saved in the same transaction as each batch
+
+	Name: name of the archiver
+
+	Archived: number of documents archived since the archiver was created
+
+	LastID: _id of the last archived document
+
+	UpdatedAt: time of the last batch
+*/
+type Progress struct {
+	Name      string      `bson:"_id"`
+	Archived  int64       `bson:"archived"`
+	LastID    interface{} `bson:"lastId"`
+	UpdatedAt time.Time   `bson:"updatedAt"`
+}
+
+/*
+Archiver object
+Moves old documents from a hot collection to an archive collection or a writer
+*/
+type Archiver struct {
+	c    *driver.Client
+	name string
+	opts Options
+}
+
+/*
+Creates an archiver
+
+	*driver.Client client with the database and the hot collection set
+
+	string: name of the archiver, identifies its checkpoint
+
+	Options options of the archiver
+
+Returns:
+
+	*Archiver pointer to an archiver
+
+	an err - error
+*/
+func New(c *driver.Client, name string, opts Options) (*Archiver, error) {
+	if opts.Field == "" || opts.MaxAge <= 0 {
+		return nil, errors.New("an archiver needs a Field and a MaxAge")
+	}
+	if opts.Archive == "" && opts.Writer == nil {
+		return nil, errors.New("an archiver needs an Archive collection or a Writer")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = time.Hour
+	}
+	if opts.Checkpoints == "" {
+		opts.Checkpoints = "archive_checkpoints"
+	}
+	return &Archiver{c: c, name: name, opts: opts}, nil
+}
+
+/*
+Archives the old documents every interval until the context is done
+Run a single archiver per name, see the leader package
+
+	context.Context context to stop with
+
+Returns:
+
+	an err - error
+*/
+func (a *Archiver) Run(ctx context.Context) error {
+	for {
+		if _, err := a.RunOnce(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(a.opts.Interval):
+		}
+	}
+}
+
+/*
+Archives the documents that are old enough now, batch by batch
+Each batch is copied to the archive collection, deleted from the hot collection
+and checkpointed in one transaction. With a Writer the batch is written before the
+transaction, so a batch may be written again if the archiver stops in between
+
+	context.Context context to stop with, checked between batches
+
+Returns:
+
+	the number of archived documents - int64
+
+	an err - error
+*/
+func (a *Archiver) RunOnce(ctx context.Context) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		n, err := a.batch(ctx)
+		total += n
+		if err != nil || n == 0 {
+			return total, err
+		}
+	}
+	return total, ctx.Err()
+}
+
+/*
+Returns the checkpoint of the archiver, nil if it never archived anything
+
+Returns:
+
+	the progress - *Progress
+
+	an err - error
+*/
+func (a *Archiver) Progress() (*Progress, error) {
+	checkpoints := a.c.With()
+	if _, err := checkpoints.SetCollection(a.opts.Checkpoints); err != nil {
+		return nil, err
+	}
+	var p Progress
+	if err := checkpoints.FindByID(a.name, &p); err != nil {
+		if errors.Is(err, driver.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+/*
+Moves one batch of documents
+*/
+func (a *Archiver) batch(ctx context.Context) (int64, error) {
+	filter := driver.F(a.opts.Field).Lt(time.Now().Add(-a.opts.MaxAge))
+	if a.opts.Filter != nil {
+		filter = driver.And(filter, a.opts.Filter)
+	}
+	var docs []bson.Raw
+	err := a.c.FindAll(filter, &docs, options.Find().SetSort(driver.Sort().Asc("_id")).SetLimit(a.opts.BatchSize))
+	if err != nil || len(docs) == 0 {
+		return 0, err
+	}
+	ids := make([]interface{}, len(docs))
+	objects := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		ids[i], objects[i] = doc.Lookup("_id"), doc
+	}
+	if a.opts.Writer != nil && a.opts.Archive == "" {
+		if err := a.opts.Writer.Write(ctx, docs); err != nil {
+			return 0, err
+		}
+	}
+
+	var deleted int64
+	err = a.c.Transaction(func(tc *driver.Client) error {
+		if a.opts.Archive != "" {
+			archive := tc.With()
+			if _, err := archive.SetCollection(a.opts.Archive); err != nil {
+				return err
+			}
+			// upserting by _id keeps the batch idempotent when the transaction is retried
+			if _, err := archive.UpsertMany(objects, "_id"); err != nil {
+				return err
+			}
+		}
+		res, err := tc.RemoveMany(driver.F("_id").In(ids...), nil)
+		if err != nil {
+			return err
+		}
+		deleted = res.Deleted
+		checkpoints := tc.With()
+		if _, err := checkpoints.SetCollection(a.opts.Checkpoints); err != nil {
+			return err
+		}
+		update := driver.Update().Inc("archived", res.Deleted).Set("lastId", ids[len(ids)-1]).Set("updatedAt", time.Now())
+		_, err = checkpoints.UpdateOne(driver.F("_id").Eq(a.name), update, options.Update().SetUpsert(true))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,317 @@
+/*
+Gomongo-gen generates typed repositories for model structs
+
+A model is a struct annotated with the collection it is stored in
+
+	//gomongo:collection users
+	type User struct {
+		ID    primitive.ObjectID `bson:"_id,omitempty"`
+		Email string             `bson:"email" index:"unique"`
+		Team  string             `bson:"team" index:""`
+	}
+
+Running it from a go:generate directive in the file of the models
+
+	//go:generate go run github.com/olympsis/go-mongo/cmd/gomongo-gen
+
+writes <file>_repo.go next to the file with, for each model, constants for the
+field names (UserEmail = "email") and a repository on top of the driver Client
+with FindByID, Save and a FindBy method for each field with an index tag.
+Fields of unique indexes find one object, the others find a slice of them
+*/
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+/*
+Directive marking a struct as a model
+*/
+const directive = "//gomongo:collection"
+
+type model struct {
+	Name       string
+	Collection string
+	Fields     []field
+}
+
+type field struct {
+	Name   string
+	Key    string
+	Type   string
+	Index  bool
+	Unique bool
+}
+
+func main() {
+	input := flag.String("file", os.Getenv("GOFILE"), "file declaring the models, defaults to the file of the go:generate directive")
+	output := flag.String("o", "", "file to write, defaults to <file>_repo.go")
+	flag.Parse()
+	if *input == "" {
+		log.Fatal("gomongo-gen: no file given, run it with go generate or set -file")
+	}
+	if *output == "" {
+		*output = strings.TrimSuffix(*input, ".go") + "_repo.go"
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, *input, nil, parser.ParseComments)
+	if err != nil {
+		log.Fatal(err)
+	}
+	models, imports := parse(fset, file)
+	if len(models) == 0 {
+		log.Fatalf("gomongo-gen: no struct of %s is annotated with %s", *input, directive)
+	}
+	src, err := generate(file.Name.Name, models, imports)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+/*
+Finds the annotated structs of a file and the imports their indexed fields need
+*/
+func parse(fset *token.FileSet, file *ast.File) ([]model, []string) {
+	paths := map[string]string{}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		paths[name] = path
+	}
+
+	var models []model
+	needed := map[string]bool{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			doc := ts.Doc
+			if doc == nil {
+				doc = gen.Doc
+			}
+			collection := annotation(doc)
+			if collection == "" {
+				continue
+			}
+			m := model{Name: ts.Name.Name, Collection: collection}
+			for _, f := range st.Fields.List {
+				if len(f.Names) == 0 {
+					continue // embedded
+				}
+				tag := reflect.StructTag("")
+				if f.Tag != nil {
+					tag = reflect.StructTag(strings.Trim(f.Tag.Value, "`"))
+				}
+				key, _, _ := strings.Cut(tag.Get("bson"), ",")
+				if key == "-" {
+					continue
+				}
+				index, indexed := tag.Lookup("index")
+				var typ bytes.Buffer
+				printer.Fprint(&typ, fset, f.Type)
+				for _, name := range f.Names {
+					if !name.IsExported() {
+						continue
+					}
+					k := key
+					if k == "" {
+						k = strings.ToLower(name.Name) // default name of the bson codec
+					}
+					m.Fields = append(m.Fields, field{
+						Name:   name.Name,
+						Key:    k,
+						Type:   typ.String(),
+						Index:  indexed && k != "_id",
+						Unique: indexed && strings.Contains(","+index+",", ",unique,"),
+					})
+				}
+				if indexed {
+					ast.Inspect(f.Type, func(n ast.Node) bool {
+						if sel, ok := n.(*ast.SelectorExpr); ok {
+							if pkg, ok := sel.X.(*ast.Ident); ok && paths[pkg.Name] != "" {
+								needed[paths[pkg.Name]] = true
+							}
+						}
+						return true
+					})
+				}
+			}
+			models = append(models, m)
+		}
+	}
+	var imports []string
+	for path := range needed {
+		imports = append(imports, path)
+	}
+	// standard library first like goimports
+	sort.Slice(imports, func(i, j int) bool {
+		si, sj := !strings.Contains(imports[i], "."), !strings.Contains(imports[j], ".")
+		if si != sj {
+			return si
+		}
+		return imports[i] < imports[j]
+	})
+	return models, imports
+}
+
+/*
+Collection named by the annotation of a struct, empty if it has none
+*/
+func annotation(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	for _, c := range doc.List {
+		if strings.HasPrefix(c.Text, directive) {
+			return strings.TrimSpace(strings.TrimPrefix(c.Text, directive))
+		}
+	}
+	return ""
+}
+
+func generate(pkg string, models []model, imports []string) ([]byte, error) {
+	var buf bytes.Buffer
+	var std []string
+	for _, path := range imports {
+		if !strings.Contains(path, ".") {
+			std = append(std, path)
+		}
+	}
+	unique := false
+	for _, m := range models {
+		for _, f := range m.Fields {
+			unique = unique || (f.Index && f.Unique)
+		}
+	}
+	err := repository.Execute(&buf, struct {
+		Package string
+		Std     []string
+		Imports []string
+		Models  []model
+		Unique  bool
+	}{pkg, std, imports[len(std):], models, unique})
+	if err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("gomongo-gen: generated invalid code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+var repository = template.Must(template.New("repository").Parse(`// Code generated by gomongo-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .Unique}}
+	"errors"
+{{- end}}
+{{- range .Std}}
+	"{{.}}"
+{{- end}}
+
+	"github.com/olympsis/go-mongo/driver"
+{{- if .Unique}}
+	"go.mongodb.org/mongo-driver/mongo"
+{{- end}}
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+{{range $m := .Models}}
+// Field names of {{$m.Name}}
+const (
+{{- range $m.Fields}}
+	{{$m.Name}}{{.Name}} = "{{.Key}}"
+{{- end}}
+)
+
+// {{$m.Name}}Repository reads and writes {{$m.Name}} objects in the {{$m.Collection}} collection
+type {{$m.Name}}Repository struct {
+	c *driver.Client
+}
+
+// New{{$m.Name}}Repository creates a repository on the database of the client
+func New{{$m.Name}}Repository(c *driver.Client) (*{{$m.Name}}Repository, error) {
+	cl, err := c.Clone("", "{{$m.Collection}}")
+	if err != nil {
+		return nil, err
+	}
+	return &{{$m.Name}}Repository{c: cl}, nil
+}
+
+// Client returns the client of the repository, set on the {{$m.Collection}} collection
+func (r *{{$m.Name}}Repository) Client() *driver.Client {
+	return r.c
+}
+
+// FindByID finds the {{$m.Name}} with an _id
+func (r *{{$m.Name}}Repository) FindByID(id interface{}) (*{{$m.Name}}, error) {
+	var v {{$m.Name}}
+	if err := r.c.FindByID(id, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Save inserts the {{$m.Name}}, or replaces it when it has an _id
+func (r *{{$m.Name}}Repository) Save(v *{{$m.Name}}) (interface{}, error) {
+	return r.c.Save(v)
+}
+{{range $m.Fields}}{{if .Index}}{{if .Unique}}
+// FindBy{{.Name}} finds the {{$m.Name}} by its {{.Key}}
+func (r *{{$m.Name}}Repository) FindBy{{.Name}}(value {{.Type}}) (*{{$m.Name}}, error) {
+	res := r.c.FindOne(driver.F({{$m.Name}}{{.Name}}).Eq(value))
+	if res == nil {
+		return nil, driver.ErrNotConnected
+	}
+	var v {{$m.Name}}
+	if err := res.Decode(&v); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, driver.ErrNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+{{else}}
+// FindBy{{.Name}} finds the {{$m.Name}} objects by their {{.Key}}
+func (r *{{$m.Name}}Repository) FindBy{{.Name}}(value {{.Type}}) ([]{{$m.Name}}, error) {
+	var v []{{$m.Name}}
+	if err := r.c.FindAll(driver.F({{$m.Name}}{{.Name}}).Eq(value), &v, nil); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+{{end}}{{end}}{{end}}{{end}}`))
@@ -0,0 +1,166 @@
+/*
+Gomongo is a small admin companion built on the driver package
+
+It connects with the same code path as the library, which makes it handy to
+smoke-test connectivity and credentials from a deployment
+
+	gomongo [flags] ping [readPreference]
+	gomongo [flags] dbs
+	gomongo [flags] -db app collections
+	gomongo [flags] -db app -c users indexes
+	gomongo [flags] -db app -c users find '{"age": {"$gt": 30}}'
+	gomongo [flags] -db app -c users export > users.ndjson
+	gomongo [flags] -db app -c users import < users.ndjson
+
+The credentials and url default to the MONGO_USER, MONGO_PASSWORD and MONGO_URL
+environment variables, the url being what follows the credentials. ex: @cluster0.smchw.mongodb.net
+*/
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/olympsis/go-mongo/driver"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	user := flag.String("user", os.Getenv("MONGO_USER"), "username to authenticate with")
+	password := flag.String("password", os.Getenv("MONGO_PASSWORD"), "password to authenticate with")
+	url := flag.String("url", os.Getenv("MONGO_URL"), "url after the credentials. ex: @cluster0.smchw.mongodb.net")
+	direct := flag.Bool("direct", false, "connect directly to a single host with mongodb:// instead of mongodb+srv://")
+	socket := flag.String("socket", "", "path of a Unix socket to connect through")
+	db := flag.String("db", "", "database to use")
+	collection := flag.String("c", "", "collection to use")
+	limit := flag.Int64("limit", 20, "maximum number of documents find prints, 0 for all")
+	timeout := flag.Duration("timeout", 30*time.Second, "timeout of each operation")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: gomongo [flags] ping [readPreference]|dbs|collections|indexes|find [filter]|export|import")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := []driver.Option{driver.WithTimeout(*timeout), driver.WithPingTimeout(*timeout)}
+	if *direct {
+		opts = append(opts, driver.WithDirectConnection())
+	}
+	if *socket != "" {
+		opts = append(opts, driver.WithUnixSocket(*socket))
+	}
+	c := driver.NewClient(*user, *password, *url, opts...)
+	if err := c.Connect(); err != nil {
+		fail(err)
+	}
+	defer c.Disconnect()
+	if *db != "" {
+		c.SetDatabase(*db)
+	}
+	if *collection != "" {
+		if _, err := c.SetCollection(*collection); err != nil {
+			fail(err)
+		}
+	}
+
+	if err := run(c, flag.Arg(0), flag.Args()[1:], *limit); err != nil {
+		c.Disconnect()
+		fail(err)
+	}
+}
+
+func run(c *driver.Client, command string, args []string, limit int64) error {
+	switch command {
+	case "ping":
+		preference := driver.ReadPrimary
+		if len(args) > 0 {
+			preference = driver.ReadPreference(args[0])
+		}
+		start := time.Now()
+		if err := c.Ping(context.Background(), preference); err != nil {
+			return err
+		}
+		fmt.Printf("ok %s\n", time.Since(start).Round(time.Millisecond))
+	case "dbs":
+		dbs, err := c.ListDatabases(nil)
+		if err != nil {
+			return err
+		}
+		for _, db := range dbs {
+			fmt.Printf("%s\t%d\n", db.Name, db.SizeOnDisk)
+		}
+	case "collections":
+		names, err := c.ListCollections(nil)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+	case "indexes":
+		indexes, err := c.ListIndexes()
+		if err != nil {
+			return err
+		}
+		for _, index := range indexes {
+			if err := printJSON(index); err != nil {
+				return err
+			}
+		}
+	case "find":
+		filter := bson.D{}
+		if len(args) > 0 {
+			if err := bson.UnmarshalExtJSON([]byte(args[0]), false, &filter); err != nil {
+				return fmt.Errorf("invalid filter: %w", err)
+			}
+		}
+		var docs []bson.Raw
+		if err := c.FindAll(filter, &docs, options.Find().SetLimit(limit)); err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if err := printJSON(doc); err != nil {
+				return err
+			}
+		}
+	case "export":
+		n, err := c.Export(os.Stdout, driver.ExportOptions{Format: driver.ExportJSON})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "exported %d documents\n", n)
+	case "import":
+		progress, err := c.ImportNDJSON(os.Stdin, driver.ImportOptions{})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "imported %d documents, %d failed\n", progress.Inserted, progress.Failed)
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+	return nil
+}
+
+/*
+Prints a document as relaxed extended JSON on one line
+*/
+func printJSON(doc interface{}) error {
+	b, err := bson.MarshalExtJSON(doc, false, false)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "gomongo:", err)
+	os.Exit(1)
+}
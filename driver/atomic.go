@@ -0,0 +1,151 @@
+package driver
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Increments a field of the first object matching the filter
+Use a negative delta to decrement, the field is created when it is missing
+
+	interface{} filter to query the object by
+
+	string: field to increment. ex: stats.views
+
+	interface{} number to add. ex: 1
+
+Returns:
+
+	the new value of the field - bson.RawValue
+
+	an err - error, ErrNotFound when no object matches
+*/
+func (c *Client) Inc(filter interface{}, field string, delta interface{}) (bson.RawValue, error) {
+	return c.atomic("inc", filter, field, Update().Inc(field, delta))
+}
+
+/*
+Appends values to an array field of the first object matching the filter
+
+	interface{} filter to query the object by
+
+	string: array field
+
+	...interface{} values to append
+
+Returns:
+
+	the new array - bson.RawValue
+
+	an err - error, ErrNotFound when no object matches
+*/
+func (c *Client) Push(filter interface{}, field string, values ...interface{}) (bson.RawValue, error) {
+	return c.atomic("push", filter, field, Update().Push(field, values...))
+}
+
+/*
+Removes the elements equal to the value, or matching it if it is a condition,
+from an array field of the first object matching the filter
+
+	interface{} filter to query the object by
+
+	string: array field
+
+	interface{} value or condition to remove. ex: bson.D{{Key: "$lt", Value: 10}}
+
+Returns:
+
+	the new array - bson.RawValue
+
+	an err - error, ErrNotFound when no object matches
+*/
+func (c *Client) Pull(filter interface{}, field string, value interface{}) (bson.RawValue, error) {
+	return c.atomic("pull", filter, field, Update().Pull(field, value))
+}
+
+/*
+Appends values to an array field of the first object matching the filter
+unless they are already in it
+
+	interface{} filter to query the object by
+
+	string: array field
+
+	...interface{} values to add
+
+Returns:
+
+	the new array - bson.RawValue
+
+	an err - error, ErrNotFound when no object matches
+*/
+func (c *Client) AddToSet(filter interface{}, field string, values ...interface{}) (bson.RawValue, error) {
+	return c.atomic("addToSet", filter, field, Update().AddToSet(field, values...))
+}
+
+/*
+Sets a field of the first object matching the filter
+
+	interface{} filter to query the object by
+
+	string: field to set
+
+	interface{} value of the field
+
+Returns:
+
+	an err - error, ErrNotFound when no object matches
+*/
+func (c *Client) SetField(filter interface{}, field string, value interface{}) error {
+	_, err := c.atomic("setField", filter, field, Update().Set(field, value))
+	return err
+}
+
+/*
+Applies an update to the first object matching the filter and returns
+the value of the field after the update
+*/
+func (c *Client) atomic(op string, filter interface{}, field string, update *UpdateBuilder) (_ bson.RawValue, err error) {
+	defer c.observe(op, time.Now(), &err)
+	if err := c.collection("updating an object"); err != nil {
+		return bson.RawValue{}, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return bson.RawValue{}, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return bson.RawValue{}, err
+	}
+	if c.cf.dryRun != nil {
+		matched, err := c.dryRun(ctx, op, filter, update.D(), true)
+		if err == nil && matched == 0 {
+			err = ErrNotFound
+		}
+		return bson.RawValue{}, err
+	}
+	opts := options.FindOneAndUpdate().
+		SetProjection(bson.D{{Key: field, Value: 1}}).
+		SetReturnDocument(options.After)
+	raw, err := c.co().FindOneAndUpdate(ctx, filter, update, c.cf.findOneAndUpdateOptions(), opts).DecodeBytes()
+	if err != nil {
+		return bson.RawValue{}, mapError(err)
+	}
+	if read := c.reader(); read != nil {
+		if raw, err = read(raw); err != nil {
+			return bson.RawValue{}, err
+		}
+	}
+	value, err := raw.LookupErr(strings.Split(field, ".")...)
+	if err != nil { // ex: positional paths, they aren't keys of the object
+		return bson.RawValue{}, nil
+	}
+	return value, nil
+}
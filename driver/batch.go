@@ -0,0 +1,260 @@
+package driver
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+BatchWriter object
+Accumulates writes and sends them to the collection as unordered bulk writes
+once enough are queued or the flush interval has elapsed
+*/
+type BatchWriter struct {
+	c       *Client
+	co      *mongo.Collection
+	size    int
+	onError func(error, []mongo.WriteModel)
+	mu      sync.Mutex
+	models  []mongo.WriteModel
+	stop    chan struct{}
+	done    chan struct{}
+	closed  bool
+}
+
+/*
+Creates a batch writer on the collection that is set
+
+	int: number of queued writes that triggers a flush
+
+	time.Duration interval between periodic flushes, 0 to only flush on size
+
+	func(error, []mongo.WriteModel) called when an automatic flush fails with the writes that were sent, can be nil
+
+Returns:
+
+	*BatchWriter pointer to a batch writer
+
+	an err - error
+*/
+func (c *Client) NewBatchWriter(size int, interval time.Duration, onError func(error, []mongo.WriteModel)) (*BatchWriter, error) {
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	if err := c.collection("creating a batch writer"); err != nil {
+		return nil, err
+	}
+	if size < 1 {
+		return nil, errors.New("batch size must be greater than 0")
+	}
+	w := &BatchWriter{
+		c:       c,
+		co:      c.co(),
+		size:    size,
+		onError: onError,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run(interval)
+	c.cn.onShutdown(w, w.shutdown)
+	return w, nil
+}
+
+/*
+Queues an insert
+*/
+func (w *BatchWriter) Insert(object interface{}) error {
+	object, err := w.c.prepare(object)
+	if err != nil {
+		return err
+	}
+	return w.add(mongo.NewInsertOneModel().SetDocument(object))
+}
+
+/*
+Queues an update of the documents matching the filter
+*/
+func (w *BatchWriter) Update(filter interface{}, update interface{}, upsert bool) error {
+	if err := ValidateUpdate(update); err != nil {
+		return err
+	}
+	return w.add(mongo.NewUpdateManyModel().SetFilter(filter).SetUpdate(update).SetUpsert(upsert))
+}
+
+/*
+Queues a replacement of the document matching the filter
+*/
+func (w *BatchWriter) Replace(filter interface{}, replacement interface{}, upsert bool) error {
+	if err := ValidateReplacement(replacement); err != nil {
+		return err
+	}
+	if err := w.c.validate(replacement); err != nil {
+		return err
+	}
+	return w.add(mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(replacement).SetUpsert(upsert))
+}
+
+/*
+Queues a deletion of the documents matching the filter
+*/
+func (w *BatchWriter) Delete(filter interface{}) error {
+	return w.add(mongo.NewDeleteManyModel().SetFilter(filter))
+}
+
+/*
+Sends the queued writes now
+
+Returns:
+
+	an err - error
+*/
+func (w *BatchWriter) Flush() error {
+	w.mu.Lock()
+	models := w.models
+	w.models = nil
+	w.mu.Unlock()
+	return w.write(models)
+}
+
+/*
+Stops the periodic flushes and sends the queued writes
+The writer can't be used afterwards
+
+Returns:
+
+	an err - error
+*/
+func (w *BatchWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+	w.c.cn.forget(w)
+	close(w.stop)
+	<-w.done
+	return w.Flush()
+}
+
+/*
+Closes the writer when the client shuts down, the queued writes are sent even though new operations are refused
+*/
+func (w *BatchWriter) shutdown() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+	close(w.stop)
+	<-w.done
+	w.mu.Lock()
+	models := w.models
+	w.models = nil
+	w.mu.Unlock()
+	return w.bulkWrite(models)
+}
+
+func (w *BatchWriter) add(model mongo.WriteModel) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return errors.New("batch writer is closed")
+	}
+	w.models = append(w.models, model)
+	var models []mongo.WriteModel
+	if len(w.models) >= w.size {
+		models = w.models
+		w.models = nil
+	}
+	w.mu.Unlock()
+	if models != nil {
+		w.report(w.write(models), models)
+	}
+	return nil
+}
+
+func (w *BatchWriter) run(interval time.Duration) {
+	defer close(w.done)
+	if interval <= 0 {
+		<-w.stop
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			models := w.models
+			w.models = nil
+			w.mu.Unlock()
+			w.report(w.write(models), models)
+		}
+	}
+}
+
+func (w *BatchWriter) write(models []mongo.WriteModel) error {
+	if len(models) == 0 {
+		return nil
+	}
+	_, cancel := w.c.operation() // the write is in flight from the ping on
+	defer cancel()
+	// ping database
+	if err := w.c.ping(); err != nil {
+		return err
+	}
+	return w.bulkWrite(models)
+}
+
+func (w *BatchWriter) bulkWrite(models []mongo.WriteModel) error {
+	defer w.c.invalidate()
+	if len(models) == 0 {
+		return nil
+	}
+	ctx, cancel := w.c.operation()
+	defer cancel()
+	if w.c.cf.dryRun != nil {
+		for _, model := range models {
+			op, filter, document, one := writeModel(model)
+			if _, err := w.c.dryRun(ctx, op, filter, document, one); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	_, err := w.co.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return mapError(err)
+}
+
+/*
+Operation, filter, document and whether it touches one document of a queued write, for dry runs
+*/
+func writeModel(model mongo.WriteModel) (string, interface{}, interface{}, bool) {
+	switch m := model.(type) {
+	case *mongo.InsertOneModel:
+		return "insertOne", nil, m.Document, true
+	case *mongo.UpdateManyModel:
+		return "updateMany", m.Filter, m.Update, false
+	case *mongo.ReplaceOneModel:
+		return "replaceOne", m.Filter, m.Replacement, true
+	case *mongo.DeleteManyModel:
+		return "deleteMany", m.Filter, nil, false
+	}
+	return "bulkWrite", nil, model, false
+}
+
+func (w *BatchWriter) report(err error, models []mongo.WriteModel) {
+	if err != nil && w.onError != nil {
+		w.onError(err, models)
+	}
+}
@@ -0,0 +1,188 @@
+package driver
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+SortBuilder object
+Builds a sort specification. ex: Sort().Asc("name").Desc("age")
+*/
+type SortBuilder struct {
+	d bson.D
+}
+
+/*
+ProjectionBuilder object
+Builds a projection. ex: Project().Include("name").Exclude("_id")
+*/
+type ProjectionBuilder struct {
+	d bson.D
+}
+
+/*
+Creates a new sort specification
+
+Returns:
+
+	*SortBuilder pointer to a sort builder
+*/
+func Sort() *SortBuilder {
+	return &SortBuilder{d: bson.D{}}
+}
+
+/*
+Sorts by the fields from smallest to largest
+
+	...string: fields to sort by
+
+Returns:
+
+	*SortBuilder pointer to the sort builder
+*/
+func (s *SortBuilder) Asc(fields ...string) *SortBuilder {
+	for _, field := range fields {
+		s.d = append(s.d, bson.E{Key: field, Value: 1})
+	}
+	return s
+}
+
+/*
+Sorts by the fields from largest to smallest
+
+	...string: fields to sort by
+
+Returns:
+
+	*SortBuilder pointer to the sort builder
+*/
+func (s *SortBuilder) Desc(fields ...string) *SortBuilder {
+	for _, field := range fields {
+		s.d = append(s.d, bson.E{Key: field, Value: -1})
+	}
+	return s
+}
+
+/*
+Sorts by the text search score, requires a $text filter
+
+	string: name of the projected score field
+
+Returns:
+
+	*SortBuilder pointer to the sort builder
+*/
+func (s *SortBuilder) TextScore(field string) *SortBuilder {
+	s.d = append(s.d, bson.E{Key: field, Value: bson.D{{Key: "$meta", Value: "textScore"}}})
+	return s
+}
+
+/*
+Returns the sort specification
+
+Returns:
+
+	a document - bson.D
+*/
+func (s *SortBuilder) D() bson.D {
+	return s.d
+}
+
+/*
+Lets the builder be passed directly wherever a sort document is expected
+*/
+func (s *SortBuilder) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(s.d)
+}
+
+/*
+Creates a new projection
+
+Returns:
+
+	*ProjectionBuilder pointer to a projection builder
+*/
+func Project() *ProjectionBuilder {
+	return &ProjectionBuilder{d: bson.D{}}
+}
+
+/*
+Includes the fields in the returned documents
+
+	...string: fields to include
+
+Returns:
+
+	*ProjectionBuilder pointer to the projection builder
+*/
+func (p *ProjectionBuilder) Include(fields ...string) *ProjectionBuilder {
+	for _, field := range fields {
+		p.d = append(p.d, bson.E{Key: field, Value: 1})
+	}
+	return p
+}
+
+/*
+Excludes the fields from the returned documents
+Apart from _id, fields can't be excluded and included in the same projection
+
+	...string: fields to exclude
+
+Returns:
+
+	*ProjectionBuilder pointer to the projection builder
+*/
+func (p *ProjectionBuilder) Exclude(fields ...string) *ProjectionBuilder {
+	for _, field := range fields {
+		p.d = append(p.d, bson.E{Key: field, Value: 0})
+	}
+	return p
+}
+
+/*
+Returns only part of an array field
+
+	string: array field
+
+	int: number of elements, negative to take them from the end
+
+Returns:
+
+	*ProjectionBuilder pointer to the projection builder
+*/
+func (p *ProjectionBuilder) Slice(field string, n int) *ProjectionBuilder {
+	p.d = append(p.d, bson.E{Key: field, Value: bson.D{{Key: "$slice", Value: n}}})
+	return p
+}
+
+/*
+Projects the text search score into a field, requires a $text filter
+
+	string: field to put the score in
+
+Returns:
+
+	*ProjectionBuilder pointer to the projection builder
+*/
+func (p *ProjectionBuilder) TextScore(field string) *ProjectionBuilder {
+	p.d = append(p.d, bson.E{Key: field, Value: bson.D{{Key: "$meta", Value: "textScore"}}})
+	return p
+}
+
+/*
+Returns the projection
+
+Returns:
+
+	a document - bson.D
+*/
+func (p *ProjectionBuilder) D() bson.D {
+	return p.d
+}
+
+/*
+Lets the builder be passed directly wherever a projection document is expected
+*/
+func (p *ProjectionBuilder) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(p.d)
+}
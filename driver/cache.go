@@ -0,0 +1,225 @@
+package driver
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+Cache interface
+Stores the documents returned by FindOne and FindByID, see WithCache
+*/
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+/*
+A cache in memory that evicts the least recently used documents once full
+*/
+type lruCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+/*
+Creates a cache in memory holding up to size documents
+
+	int maximum number of documents
+
+Returns:
+
+	a cache - Cache
+*/
+func NewLRUCache(size int) Cache {
+	return &lruCache{size: size, ll: list.New(), items: map[string]*list.Element{}}
+}
+
+func (l *lruCache) Get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		l.ll.Remove(e)
+		delete(l.items, key)
+		return nil, false
+	}
+	l.ll.MoveToFront(e)
+	return entry.value, true
+}
+
+func (l *lruCache) Set(key string, value []byte, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e, ok := l.items[key]; ok {
+		e.Value = &lruEntry{key: key, value: value, expires: time.Now().Add(ttl)}
+		l.ll.MoveToFront(e)
+		return
+	}
+	l.items[key] = l.ll.PushFront(&lruEntry{key: key, value: value, expires: time.Now().Add(ttl)})
+	for l.ll.Len() > l.size {
+		e := l.ll.Back()
+		l.ll.Remove(e)
+		delete(l.items, e.Value.(*lruEntry).key)
+	}
+}
+
+/*
+Cache of a client and its copies
+Each collection has a generation that is part of the keys, writes bump it so
+the documents cached before them are not read anymore. Databases have one
+too, under their name, bumped when they are dropped
+*/
+type readCache struct {
+	store Cache
+	ttl   time.Duration
+	mu    sync.Mutex
+	gens  map[string]uint64
+}
+
+/*
+Caches the documents returned by FindOne and FindByID for the ttl
+Writes made through the client, or any client created from it, invalidate the
+documents cached for their collection. Writes made by other clients are only
+seen once the ttl expires. Reads made inside a session or a transaction skip
+the cache so they keep their consistency guarantees
+
+	Cache cache to store the documents in. ex: NewLRUCache(10000)
+
+	time.Duration time to keep the documents for
+
+Returns:
+
+	an option - Option
+*/
+func WithCache(cache Cache, ttl time.Duration) Option {
+	return func(cf *config) {
+		cf.cache = &readCache{store: cache, ttl: ttl, gens: map[string]uint64{}}
+	}
+}
+
+/*
+Finds one object, using the cache when it is set
+*/
+func (c *Client) findOneCached(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	rc := c.cf.cache
+	if rc == nil || mongo.SessionFromContext(ctx) != nil {
+		return c.co().FindOne(ctx, filter, c.cf.findOneOptions())
+	}
+	key, err := c.cacheKey(filter)
+	if err != nil {
+		return errorResult(err)
+	}
+	if raw, ok := rc.store.Get(key); ok {
+		return mongo.NewSingleResultFromDocument(bson.Raw(raw), nil, nil)
+	}
+	res := c.co().FindOne(ctx, filter, c.cf.findOneOptions())
+	raw, err := res.DecodeBytes()
+	if err != nil {
+		return res
+	}
+	rc.store.Set(key, raw, rc.ttl)
+	return mongo.NewSingleResultFromDocument(raw, nil, nil)
+}
+
+/*
+Key of a filter in the cache, made of the collection, its generation,
+the options changing what is read and a hash of the filter
+*/
+func (c *Client) cacheKey(filter interface{}) (string, error) {
+	b, err := bson.MarshalWithRegistry(c.cf.codecRegistry(), filter)
+	if err != nil {
+		return "", err
+	}
+	ns := c.namespace()
+	rc := c.cf.cache
+	rc.mu.Lock()
+	gen := strconv.FormatUint(rc.gens[ns], 10) + "." + strconv.FormatUint(rc.gens[c.co().Database().Name()], 10)
+	rc.mu.Unlock()
+	sum := sha256.Sum256(b)
+	return ns + ":" + gen + ":" + hex.EncodeToString(sum[:]) + c.cf.readKey(), nil
+}
+
+/*
+Part of the keys of cached and coalesced reads made of the options changing their
+result: the collation, the hint, the read preference and the read concern
+*/
+func (cf *config) readKey() string {
+	key := ""
+	if cf.collation != nil {
+		key += fmt.Sprintf(":collation=%+v", *cf.collation)
+	}
+	if cf.hint != nil {
+		key += fmt.Sprintf(":hint=%v", cf.hint)
+	}
+	if rp := cf.readPreference(); rp != nil {
+		key += ":read=" + rp.String()
+	}
+	if cf.readConcern != nil {
+		key += ":concern=" + cf.readConcern.GetLevel()
+	}
+	return key
+}
+
+/*
+Invalidates the documents cached for the collection
+*/
+func (c *Client) invalidate() {
+	if c.co() == nil {
+		return
+	}
+	c.invalidateNamespace(c.namespace())
+}
+
+/*
+Drops the cached results of a namespace written to by another collection. ex: $merge
+*/
+func (c *Client) invalidateNamespace(ns string) {
+	c.cf.counts.drop(ns)
+	rc := c.cf.cache
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	rc.gens[ns]++
+	rc.mu.Unlock()
+}
+
+/*
+Drops the cached results of every collection of a database. ex: after dropping it
+*/
+func (c *Client) invalidateDatabase(db string) {
+	c.cf.counts.dropDatabase(db)
+	rc := c.cf.cache
+	if rc == nil {
+		return
+	}
+	rc.mu.Lock()
+	rc.gens[db]++ // database names can't hold a dot, they don't clash with namespaces
+	rc.mu.Unlock()
+}
+
+func (c *Client) namespace() string {
+	return c.co().Database().Name() + "." + c.co().Name()
+}
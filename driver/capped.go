@@ -0,0 +1,149 @@
+package driver
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Tailer object
+Iterates over the documents of a capped collection as they are inserted
+*/
+type Tailer struct {
+	c      *Client
+	filter interface{}
+	cur    *mongo.Cursor
+	last   interface{}
+}
+
+/*
+Creates a capped collection in the database
+Once full, the oldest documents are overwritten by the new ones
+
+	string: name of the collection
+
+	int64 maximum size of the collection in bytes
+
+	int64 maximum number of documents, 0 for no maximum
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) CreateCappedCollection(name string, size int64, max int64) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(size)
+	if max > 0 {
+		opts.SetMaxDocuments(max)
+	}
+	return c.CreateCollection(name, opts)
+}
+
+/*
+Opens a tailable cursor on the capped collection
+The tailer first returns the documents already in the collection
+matching the filter, then waits for new ones
+
+	interface{} filter to query objects by
+
+Returns:
+
+	*Tailer pointer to a tailer
+
+	an err - error
+*/
+func (c *Client) Tail(filter interface{}) (*Tailer, error) {
+	if err := c.collection("tailing the collection"); err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	t := &Tailer{c: c, filter: filter}
+	if err := t.open(context.Background()); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+/*
+Waits for the next document and decodes it into result
+Blocks until a document is inserted, an error occurs or the context is done
+
+	context.Context context to stop waiting with
+
+	interface{} pointer to decode the document into
+
+Returns:
+
+	an err - error
+*/
+func (t *Tailer) Next(ctx context.Context, result interface{}) error {
+	for {
+		if t.cur.Next(ctx) {
+			doc := t.cur.Current
+			t.last = doc.Lookup("_id")
+			if read := t.c.reader(); read != nil {
+				var err error
+				if doc, err = read(doc); err != nil {
+					return err
+				}
+			}
+			return bson.UnmarshalWithRegistry(t.c.cf.codecRegistry(), doc, result)
+		}
+		if err := t.cur.Err(); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// the cursor dies when the collection is empty or it falls behind, reopen it
+		if t.cur.ID() == 0 {
+			t.cur.Close(ctx)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+			}
+			if err := t.open(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+/*
+Closes the tailer
+
+Returns:
+
+	an err - error
+*/
+func (t *Tailer) Close() error {
+	return t.cur.Close(context.Background())
+}
+
+/*
+Opens the cursor after the last document returned
+*/
+func (t *Tailer) open(ctx context.Context) error {
+	filter := t.filter
+	if t.last != nil {
+		filter = bson.D{{Key: "$and", Value: bson.A{filter, F("_id").Gt(t.last)}}}
+	}
+	if err := t.c.ping(); err != nil {
+		return err
+	}
+	cur, err := t.c.co().Find(ctx, filter, t.c.cf.findOptions(), options.Find().SetCursorType(options.TailableAwait))
+	if err != nil {
+		return err
+	}
+	t.cur = cur
+	return nil
+}
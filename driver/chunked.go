@@ -0,0 +1,211 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxBSONSize       = 16 * 1024 * 1024 // largest document the server accepts
+	maxWriteBatchSize = 100000           // largest number of writes in a single command
+)
+
+/*
+ChunkError object
+Contains the error of a chunk inserted by InsertManyChunked
+
+	Chunk: index of the chunk
+
+	Offset: index in the input slice of the first object of the chunk
+
+	Size: number of objects in the chunk
+
+	Err: error returned when inserting the chunk
+*/
+type ChunkError struct {
+	Chunk  int
+	Offset int
+	Size   int
+	Err    error
+}
+
+/*
+ChunkedInsertError object
+Contains the errors of every chunk that failed to be inserted
+*/
+type ChunkedInsertError struct {
+	Errors []ChunkError
+}
+
+func (e *ChunkedInsertError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, ce := range e.Errors {
+		msgs = append(msgs, fmt.Sprintf("chunk %d (objects %d to %d): %v", ce.Chunk, ce.Offset, ce.Offset+ce.Size-1, ce.Err))
+	}
+	return fmt.Sprintf("%d chunks failed to insert: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+/*
+Matches the error of any chunk, ex: errors.Is(err, ErrDuplicateKey)
+*/
+func (e *ChunkedInsertError) Is(target error) bool {
+	for _, ce := range e.Errors {
+		if errors.Is(ce.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Finds the first error of a chunk matching target, ex: to get the mongo.BulkWriteException of a chunk
+*/
+func (e *ChunkedInsertError) As(target interface{}) bool {
+	for _, ce := range e.Errors {
+		if errors.As(ce.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+A chunk of encoded objects
+*/
+type chunk struct {
+	index  int
+	offset int
+	docs   []interface{}
+}
+
+/*
+Inserts a large slice of objects in chunks using several workers
+Chunks stay under the 16MB and 100,000 writes limits of a single insert.
+Chunks are inserted concurrently so the order of the objects is not kept.
+Objects get their defaults, validation and size guard as with InsertMany,
+and each chunk has the timeout of a single operation
+
+	[]interface{} objects to insert in collection
+
+	int: number of chunks inserted at the same time, 1 when less than 1
+
+	int: maximum number of objects per chunk, 0 for the server maximum
+
+Returns:
+
+	the number of inserted objects - int
+
+	an err - error, a *ChunkedInsertError when some chunks failed
+*/
+func (c *Client) InsertManyChunked(objects []interface{}, workers int, chunkSize int) (_ int, err error) {
+	defer c.observe("insertManyChunked", time.Now(), &err)
+	if err := c.collection("inserting objects"); err != nil {
+		return 0, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return 0, err
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if chunkSize < 1 || chunkSize > maxWriteBatchSize {
+		chunkSize = maxWriteBatchSize
+	}
+	ctx, cancel := c.stream()
+	defer cancel()
+	docs, _, err := c.prepareInserts(objects)
+	if err != nil {
+		return 0, err
+	}
+	// ping database
+	if err := c.ping(); err != nil {
+		return 0, err
+	}
+	if c.cf.dryRun != nil {
+		if _, err := c.dryRun(ctx, "insertManyChunked", nil, docs, false); err != nil {
+			return 0, err
+		}
+		return len(docs), nil
+	}
+	if err := c.fitAll(docs); err != nil {
+		return 0, err
+	}
+	chunks, err := split(c.cf.codecRegistry(), docs, chunkSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		inserted int
+		failed   []ChunkError
+	)
+	queue := make(chan chunk)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ch := range queue {
+				ctx, cancel := c.step(ctx)
+				res, err := c.co().InsertMany(ctx, ch.docs, options.InsertMany().SetOrdered(false))
+				cancel()
+				mu.Lock()
+				if res != nil {
+					inserted += len(res.InsertedIDs)
+				}
+				if err != nil {
+					failed = append(failed, ChunkError{Chunk: ch.index, Offset: ch.offset, Size: len(ch.docs), Err: mapError(err)})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, ch := range chunks {
+		queue <- ch
+	}
+	close(queue)
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return inserted, &ChunkedInsertError{Errors: failed}
+	}
+	return inserted, nil
+}
+
+/*
+Encodes the objects and splits them into chunks
+*/
+func split(reg *bsoncodec.Registry, objects []interface{}, chunkSize int) ([]chunk, error) {
+	var chunks []chunk
+	current := chunk{}
+	bytes := 0
+	for i, object := range objects {
+		b, err := bson.MarshalWithRegistry(reg, object)
+		if err != nil {
+			return nil, fmt.Errorf("object %d: %w", i, err)
+		}
+		if len(b) > maxBSONSize {
+			return nil, fmt.Errorf("object %d: document is larger than 16MB", i)
+		}
+		if len(current.docs) == chunkSize || bytes+len(b) > maxBSONSize {
+			chunks = append(chunks, current)
+			current = chunk{index: len(chunks), offset: i}
+			bytes = 0
+		}
+		current.docs = append(current.docs, bson.Raw(b))
+		bytes += len(b)
+	}
+	if len(current.docs) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks, nil
+}
@@ -0,0 +1,38 @@
+package driver
+
+import (
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+)
+
+/*
+Adds encoders and decoders to the codec registry of the client, ex: to encode nil slices as empty
+arrays rb.RegisterDefaultEncoder(reflect.Slice, bsoncodec.NewSliceCodec(bsonoptions.SliceCodec().SetEncodeNilAsEmpty(true)))
+
+	func(*bsoncodec.RegistryBuilder): registers the codecs on top of the default ones
+
+Returns:
+
+	an option - Option
+*/
+func WithCodecs(register func(*bsoncodec.RegistryBuilder)) Option {
+	return func(cf *config) {
+		cf.codecs = append(cf.codecs, register)
+	}
+}
+
+/*
+Uses a complete codec registry to encode and decode documents
+It replaces the codecs of WithCodecs, WithDecimalCodec and WithUUIDCodec, use
+WithCodecs instead to add codecs to the default registry
+
+	*bsoncodec.Registry registry to use
+
+Returns:
+
+	an option - Option
+*/
+func WithRegistry(registry *bsoncodec.Registry) Option {
+	return func(cf *config) {
+		cf.registry = registry
+	}
+}
@@ -0,0 +1,148 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Checks if a collection exists in the database
+
+	string: name of the collection
+
+Returns:
+
+	a boolean - bool
+
+	an err - error
+*/
+func (c *Client) CollectionExists(name string) (bool, error) {
+	names, err := c.ListCollections(bson.D{{Key: "name", Value: name}})
+	if err != nil {
+		return false, err
+	}
+	return len(names) > 0, nil
+}
+
+/*
+Creates a collection in the database
+
+	string: name of the collection
+
+	*options.CreateCollectionOptions options to create the collection with
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) CreateCollection(name string, opts *options.CreateCollectionOptions) (err error) {
+	defer c.observe("createCollection", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if c.db() == nil {
+		return noDatabase("creating a collection")
+	}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("createCollection", bson.D{{Key: "create", Value: name}})
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	return mapError(c.db().CreateCollection(ctx, name, opts))
+}
+
+/*
+Drops a collection and its indexes from the database
+Dropping a collection that doesn't exist is not an error
+
+	string: name of the collection
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) DropCollection(name string) (err error) {
+	defer c.observe("dropCollection", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if c.db() == nil {
+		return noDatabase("dropping a collection")
+	}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("dropCollection", bson.D{{Key: "drop", Value: name}})
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	if err := c.db().Collection(name).Drop(ctx); err != nil {
+		return mapError(err)
+	}
+	c.invalidateNamespace(c.db().Name() + "." + name)
+	return nil
+}
+
+/*
+Renames a collection of the database
+
+	string: current name of the collection
+
+	string: new name of the collection
+
+	bool: drop the collection with the new name if it already exists
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) RenameCollection(from string, to string, dropTarget bool) (err error) {
+	defer c.observe("renameCollection", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if c.db() == nil {
+		return noDatabase("renaming a collection")
+	}
+	cmd := bson.D{
+		{Key: "renameCollection", Value: c.db().Name() + "." + from},
+		{Key: "to", Value: c.db().Name() + "." + to},
+		{Key: "dropTarget", Value: dropTarget},
+	}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("renameCollection", cmd)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	if err := c.cl().Database("admin").RunCommand(ctx, cmd).Err(); err != nil {
+		return mapError(err)
+	}
+	c.invalidateNamespace(c.db().Name() + "." + from)
+	c.invalidateNamespace(c.db().Name() + "." + to)
+	return nil
+}
+
+/*
+Lists the names of the collections in the database
+
+	interface{} filter on the collection specifications, nil for every collection. ex: bson.D{{Key: "type", Value: "view"}}
+
+Returns:
+
+	the names of the collections - []string
+
+	an err - error
+*/
+func (c *Client) ListCollections(filter interface{}) (_ []string, err error) {
+	defer c.observe("listCollections", time.Now(), &err)
+	if c.db() == nil {
+		return nil, noDatabase("listing collections")
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	names, err := c.db().ListCollectionNames(ctx, filter)
+	return names, mapError(err)
+}
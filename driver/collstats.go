@@ -0,0 +1,78 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+CollectionStats object
+Contains the storage statistics of a collection, sizes are in bytes
+On sharded clusters the statistics of every shard are added up
+*/
+type CollectionStats struct {
+	Namespace      string           `json:"ns"`
+	Count          int64            `json:"count"`
+	Size           int64            `json:"size"`
+	AvgObjSize     int64            `json:"avgObjSize"`
+	StorageSize    int64            `json:"storageSize"`
+	Indexes        int64            `json:"nindexes"`
+	TotalIndexSize int64            `json:"totalIndexSize"`
+	IndexSizes     map[string]int64 `json:"indexSizes"`
+}
+
+/*
+Returns the storage statistics of the collection that is set
+
+Returns:
+
+	the statistics - *CollectionStats
+
+	an err - error
+*/
+func (c *Client) CollectionStats() (_ *CollectionStats, err error) {
+	defer c.observe("collectionStats", time.Now(), &err)
+	if err := c.collection("getting its stats"); err != nil {
+		return nil, err
+	}
+	p := Pipeline().Stage("$collStats", bson.D{{Key: "storageStats", Value: bson.D{}}})
+	ctx, cancel := c.operation()
+	defer cancel()
+	cursor, err := c.co().Aggregate(ctx, p.Stages())
+	if err != nil {
+		return nil, mapError(err)
+	}
+	var shards []struct {
+		Namespace    string `bson:"ns"`
+		StorageStats struct {
+			Count          int64            `bson:"count"`
+			Size           int64            `bson:"size"`
+			StorageSize    int64            `bson:"storageSize"`
+			Indexes        int64            `bson:"nindexes"`
+			TotalIndexSize int64            `bson:"totalIndexSize"`
+			IndexSizes     map[string]int64 `bson:"indexSizes"`
+		} `bson:"storageStats"`
+	}
+	if err := cursor.All(ctx, &shards); err != nil {
+		return nil, mapError(err)
+	}
+	stats := &CollectionStats{IndexSizes: map[string]int64{}}
+	for _, shard := range shards {
+		stats.Namespace = shard.Namespace
+		stats.Count += shard.StorageStats.Count
+		stats.Size += shard.StorageStats.Size
+		stats.StorageSize += shard.StorageStats.StorageSize
+		stats.TotalIndexSize += shard.StorageStats.TotalIndexSize
+		if shard.StorageStats.Indexes > stats.Indexes {
+			stats.Indexes = shard.StorageStats.Indexes
+		}
+		for name, size := range shard.StorageStats.IndexSizes {
+			stats.IndexSizes[name] += size
+		}
+	}
+	if stats.Count > 0 {
+		stats.AvgObjSize = stats.Size / stats.Count
+	}
+	return stats, nil
+}
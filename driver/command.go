@@ -0,0 +1,41 @@
+package driver
+
+import (
+	"errors"
+	"time"
+)
+
+/*
+Runs a database command and decodes its result
+Escape hatch for the commands the client doesn't have a method for
+
+	string: database to run the command on, empty for the database that is set
+
+	interface{} command to run, must be ordered. ex: bson.D{{Key: "ping", Value: 1}}
+
+	interface{} pointer to decode the result into, nil to ignore it
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) RunCommand(db string, cmd interface{}, result interface{}) (err error) {
+	defer c.observe("runCommand", time.Now(), &err)
+	if c.connected() != nil {
+		return notConnected("running a command")
+	}
+	database := c.db()
+	if db != "" {
+		database = c.cl().Database(db)
+	}
+	if database == nil {
+		return &clientError{kind: ErrNoDatabaseSet, err: errors.New("please set a database or name one to run the command on")}
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	res := database.RunCommand(ctx, cmd)
+	if result == nil {
+		return mapError(res.Err())
+	}
+	return mapError(res.Decode(result))
+}
@@ -0,0 +1,264 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+/*
+ConnState describes the connection of a client to the cluster
+*/
+type ConnState int
+
+const (
+	StateDisconnected ConnState = iota
+	StateConnecting
+	StateConnected
+	StateReconnecting
+)
+
+/*
+Longest wait between two checks of the monitor while the cluster is unreachable
+*/
+const maxMonitorBackoff = time.Minute
+
+func (s ConnState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	}
+	return "disconnected"
+}
+
+/*
+Connection shared by a client and every client created from it with With
+*/
+type connection struct {
+	dial      sync.Mutex
+	mu        sync.Mutex
+	cl        *mongo.Client
+	state     ConnState
+	listeners []func(ConnState)
+	stop      chan struct{}
+	draining  bool
+	inflight  int
+	closers   map[interface{}]func() error
+	redialing bool
+	failures  []func(ErrorEvent)
+	topology  []func(TopologyEvent)
+	pool      poolCounters
+}
+
+/*
+Client, database and collection handles of a client
+They are replaced as a whole so operations running on other goroutines
+never see a handle made on another connection than its parent
+*/
+type handles struct {
+	cl *mongo.Client
+	db *mongo.Database
+	co *mongo.Collection
+}
+
+func (c *Client) cl() *mongo.Client {
+	if h := c.hs.Load(); h != nil {
+		return h.cl
+	}
+	return nil
+}
+
+func (c *Client) db() *mongo.Database {
+	if h := c.hs.Load(); h != nil {
+		return h.db
+	}
+	return nil
+}
+
+func (c *Client) co() *mongo.Collection {
+	if h := c.hs.Load(); h != nil {
+		return h.co
+	}
+	return nil
+}
+
+/*
+Publishes a changed copy of the handles of the client
+*/
+func (c *Client) bind(change func(*handles)) {
+	for {
+		h := c.hs.Load()
+		bound := &handles{}
+		if h != nil {
+			*bound = *h
+		}
+		change(bound)
+		if c.hs.CompareAndSwap(h, bound) {
+			return
+		}
+	}
+}
+
+/*
+Copy of the client sharing its connection, with handles of its own
+*/
+func (c *Client) copy() *Client {
+	client := *c
+	client.hs = &atomic.Pointer[handles]{}
+	client.hs.Store(c.hs.Load())
+	return &client
+}
+
+/*
+Checks the connection in the background and reports its state changes
+The driver reconnects by itself once the cluster is reachable again, the
+monitor checks it with an exponential backoff until it is. See OnStateChange
+
+	time.Duration time between two checks
+
+Returns:
+
+	an option - Option
+*/
+func WithMonitor(interval time.Duration) Option {
+	return func(cf *config) {
+		cf.monitor = interval
+	}
+}
+
+/*
+Registers a callback called when the state of the connection changes
+Callbacks are called one at a time and should return quickly
+
+	func(ConnState): callback receiving the new state
+*/
+func (c *Client) OnStateChange(callback func(ConnState)) {
+	c.cn.mu.Lock()
+	defer c.cn.mu.Unlock()
+	c.cn.listeners = append(c.cn.listeners, callback)
+}
+
+/*
+State of the connection as last seen by Ping or the monitor
+
+Returns:
+
+	the state - ConnState
+*/
+func (c *Client) State() ConnState {
+	c.cn.mu.Lock()
+	defer c.cn.mu.Unlock()
+	return c.cn.state
+}
+
+/*
+Connects on first use
+Clients created with With reuse the connection made by any of them, and
+pick up the new one when it was replaced by Reconnect
+*/
+func (c *Client) connected() error {
+	if c.cn.client() == nil {
+		c.cn.dial.Lock()
+		// another caller may have connected while this one waited
+		if c.cn.client() == nil {
+			if err := c.connect(); err != nil {
+				c.cn.dial.Unlock()
+				return err
+			}
+		}
+		c.cn.dial.Unlock()
+	}
+	return c.rebind()
+}
+
+/*
+Current client of the connection, nil when it isn't connected
+*/
+func (cn *connection) client() *mongo.Client {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+	return cn.cl
+}
+
+/*
+Sets the connection once connected, restarting the monitor on it if there is one
+Returns the connection it replaces, if any
+*/
+func (cn *connection) connected(cl *mongo.Client, monitor time.Duration) *mongo.Client {
+	cn.mu.Lock()
+	stale := cn.cl
+	cn.cl = cl
+	if cn.stop != nil {
+		close(cn.stop)
+		cn.stop = nil
+	}
+	if monitor > 0 {
+		cn.stop = make(chan struct{})
+		go cn.monitor(cl, monitor, cn.stop)
+	}
+	cn.mu.Unlock()
+	return stale
+}
+
+/*
+Stops the monitor and forgets the connection
+*/
+func (cn *connection) disconnected() {
+	cn.mu.Lock()
+	if cn.stop != nil {
+		close(cn.stop)
+		cn.stop = nil
+	}
+	cn.cl = nil
+	cn.mu.Unlock()
+	cn.set(StateDisconnected)
+}
+
+/*
+Changes the state, calling the callbacks if it changed
+*/
+func (cn *connection) set(state ConnState) {
+	cn.mu.Lock()
+	if cn.state == state {
+		cn.mu.Unlock()
+		return
+	}
+	cn.state = state
+	listeners := cn.listeners
+	cn.mu.Unlock()
+	for _, listener := range listeners {
+		listener(state)
+	}
+}
+
+func (cn *connection) monitor(cl *mongo.Client, interval time.Duration, stop chan struct{}) {
+	wait := interval
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(wait):
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		err := cl.Ping(ctx, readpref.Primary())
+		cancel()
+		if err == nil {
+			cn.set(StateConnected)
+			wait = interval
+			continue
+		}
+		cn.set(StateReconnecting)
+		// back off while the driver reconnects
+		if wait *= 2; wait > maxMonitorBackoff {
+			wait = maxMonitorBackoff
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+Counts cached by CountFast, by namespace then by key
+*/
+type countCache struct {
+	ttl    time.Duration
+	mu     sync.Mutex
+	counts map[string]map[string]cachedCount
+}
+
+type cachedCount struct {
+	n       int64
+	expires time.Time
+}
+
+/*
+Caches the counts returned by CountFast for the ttl, keep it short
+Writes made through the client, or any client created from it, drop the
+counts cached for their collection
+
+	time.Duration time to keep the counts for. ex: 10 * time.Second
+
+Returns:
+
+	an option - Option
+*/
+func WithCountCache(ttl time.Duration) Option {
+	return func(cf *config) {
+		cf.counts = &countCache{ttl: ttl, counts: map[string]map[string]cachedCount{}}
+	}
+}
+
+/*
+Counts the objects matching a filter, trading exactness for speed
+Counts are served from the cache set by WithCountCache when they are fresh,
+except in sessions where they may include uncommitted writes.
+An empty filter uses the collection metadata (estimatedDocumentCount), which
+can be off after an unclean shutdown or during orphaned chunk migrations on
+sharded clusters, other filters fall back to an exact count
+
+	interface{} filter to query objects by, nil for every object
+
+Returns:
+
+	the number of objects - int64
+
+	an err - error
+*/
+func (c *Client) CountFast(filter interface{}) (_ int64, err error) {
+	defer c.observe("count", time.Now(), &err)
+	if err := c.collection("counting objects"); err != nil {
+		return 0, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	empty, err := isEmptyFilter(c.cf.codecRegistry(), filter)
+	if err != nil {
+		return 0, err
+	}
+	op := "count"
+	if empty {
+		op = "estimatedCount"
+	}
+	key, err := c.flightKey(op, filter)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// counts in a session may see its uncommitted writes, they aren't cached
+	cached := mongo.SessionFromContext(ctx) == nil
+	if n, ok := c.cf.counts.get(c.namespace(), key); ok && cached {
+		return n, nil
+	}
+	// ping database
+	if err := c.ping(); err != nil {
+		return 0, err
+	}
+	var n int64
+	if empty {
+		n, err = c.estimatedCount(ctx)
+	} else {
+		n, err = c.count(ctx, filter)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if cached {
+		c.cf.counts.set(c.namespace(), key, n)
+	}
+	return n, nil
+}
+
+/*
+Estimates the number of objects of the collection from its metadata
+*/
+func (c *Client) estimatedCount(ctx context.Context) (int64, error) {
+	if c.cf.flight == nil {
+		n, err := c.co().EstimatedDocumentCount(ctx)
+		return n, mapError(err)
+	}
+	key, err := c.flightKey("estimatedCount", bson.D{})
+	if err != nil {
+		return 0, err
+	}
+	n, err := c.cf.flight.do(key, func() (interface{}, error) {
+		return c.co().EstimatedDocumentCount(ctx)
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return n.(int64), nil
+}
+
+/*
+Checks if a filter matches every object
+*/
+func isEmptyFilter(reg *bsoncodec.Registry, filter interface{}) (bool, error) {
+	b, err := bson.MarshalWithRegistry(reg, filter)
+	if err != nil {
+		return false, err
+	}
+	// an empty document is its length and a terminating null byte
+	return len(b) == 5, nil
+}
+
+func (cc *countCache) get(ns string, key string) (int64, bool) {
+	if cc == nil {
+		return 0, false
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	count, ok := cc.counts[ns][key]
+	if !ok || time.Now().After(count.expires) {
+		return 0, false
+	}
+	return count.n, true
+}
+
+func (cc *countCache) set(ns string, key string, n int64) {
+	if cc == nil {
+		return
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	counts, ok := cc.counts[ns]
+	if !ok {
+		counts = map[string]cachedCount{}
+		cc.counts[ns] = counts
+	}
+	now := time.Now()
+	// drop the expired counts so filters that are never counted again don't pile up
+	for k, count := range counts {
+		if now.After(count.expires) {
+			delete(counts, k)
+		}
+	}
+	counts[key] = cachedCount{n: n, expires: now.Add(cc.ttl)}
+}
+
+/*
+Drops the counts cached for a namespace
+*/
+func (cc *countCache) drop(ns string) {
+	if cc == nil {
+		return
+	}
+	cc.mu.Lock()
+	delete(cc.counts, ns)
+	cc.mu.Unlock()
+}
+
+func (cc *countCache) dropDatabase(db string) {
+	if cc == nil {
+		return
+	}
+	cc.mu.Lock()
+	for ns := range cc.counts {
+		if strings.HasPrefix(ns, db+".") {
+			delete(cc.counts, ns)
+		}
+	}
+	cc.mu.Unlock()
+}
@@ -0,0 +1,118 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+DatabaseInfo object
+Contains a database returned by ListDatabases
+*/
+type DatabaseInfo struct {
+	Name       string `bson:"name" json:"name"`
+	SizeOnDisk int64  `bson:"sizeOnDisk" json:"sizeOnDisk"`
+	Empty      bool   `bson:"empty" json:"empty"`
+}
+
+/*
+DBStats object
+Contains the storage statistics of a database, sizes are in bytes
+*/
+type DBStats struct {
+	DB          string  `bson:"db" json:"db"`
+	Collections int64   `bson:"collections" json:"collections"`
+	Views       int64   `bson:"views" json:"views"`
+	Objects     int64   `bson:"objects" json:"objects"`
+	AvgObjSize  float64 `bson:"avgObjSize" json:"avgObjSize"`
+	DataSize    float64 `bson:"dataSize" json:"dataSize"`
+	StorageSize float64 `bson:"storageSize" json:"storageSize"`
+	Indexes     int64   `bson:"indexes" json:"indexes"`
+	IndexSize   float64 `bson:"indexSize" json:"indexSize"`
+	TotalSize   float64 `bson:"totalSize" json:"totalSize"`
+}
+
+/*
+Lists the databases of the deployment
+
+	interface{} filter on the databases, nil for every database. ex: bson.D{{Key: "empty", Value: false}}
+
+Returns:
+
+	the databases - []DatabaseInfo
+
+	an err - error
+*/
+func (c *Client) ListDatabases(filter interface{}) (_ []DatabaseInfo, err error) {
+	defer c.observe("listDatabases", time.Now(), &err)
+	if c.connected() != nil {
+		return nil, notConnected("listing databases")
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	res, err := c.cl().ListDatabases(ctx, filter)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	databases := make([]DatabaseInfo, 0, len(res.Databases))
+	for _, db := range res.Databases {
+		databases = append(databases, DatabaseInfo{Name: db.Name, SizeOnDisk: db.SizeOnDisk, Empty: db.Empty})
+	}
+	return databases, nil
+}
+
+/*
+Drops a database with all of its collections
+
+	string: name of the database
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) DropDatabase(name string) (err error) {
+	defer c.observe("dropDatabase", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if c.connected() != nil {
+		return notConnected("dropping a database")
+	}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("dropDatabase", bson.D{{Key: "dropDatabase", Value: name}})
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	if err := c.cl().Database(name).Drop(ctx); err != nil {
+		return mapError(err)
+	}
+	c.invalidateDatabase(name)
+	return nil
+}
+
+/*
+Returns the storage statistics of the database that is set
+
+Returns:
+
+	the statistics - *DBStats
+
+	an err - error
+*/
+func (c *Client) DBStats() (_ *DBStats, err error) {
+	defer c.observe("dbStats", time.Now(), &err)
+	if c.db() == nil {
+		return nil, noDatabase("getting its stats")
+	}
+	var stats DBStats
+	ctx, cancel := c.operation()
+	defer cancel()
+	if err := c.db().RunCommand(ctx, bson.D{{Key: "dbStats", Value: 1}}).Decode(&stats); err != nil {
+		return nil, mapError(err)
+	}
+	return &stats, nil
+}
@@ -0,0 +1,63 @@
+package driver
+
+import (
+	"time"
+)
+
+/*
+Deadline of the operations of a client that has no timeout set
+It keeps an operation from running forever when the server or the network hangs
+*/
+const DefaultTimeout = 5 * time.Minute
+
+/*
+Sets how long the operations on a collection can run before they are stopped
+It overrides the timeout of the client, set with WithTimeout on NewClient, and is
+overridden by the timeout of a single call, set with c.With(WithTimeout(d)).
+The deadline of the context of the client, see WithContext, is kept when it is earlier
+
+	string: name of the collection
+
+	time.Duration time an operation on the collection can run for, 0 for no deadline
+
+Returns:
+
+	an option - Option
+*/
+func WithCollectionTimeout(collection string, timeout time.Duration) Option {
+	return func(cf *config) {
+		timeouts := make(map[string]time.Duration, len(cf.collectionTimeouts)+1)
+		for name, t := range cf.collectionTimeouts {
+			timeouts[name] = t
+		}
+		timeouts[collection] = timeout
+		cf.collectionTimeouts = timeouts
+	}
+}
+
+/*
+Timeout of the operations, from the most to the least specific of the call,
+the collection and the client, false when none is set
+*/
+func (cf *config) deadline() (time.Duration, bool) {
+	if cf.callTimeout && cf.timeout != nil {
+		return *cf.timeout, true
+	}
+	if cf.scopedTimeout != nil {
+		return *cf.scopedTimeout, true
+	}
+	if cf.timeout != nil {
+		return *cf.timeout, true
+	}
+	return 0, false
+}
+
+/*
+Sets the timeout of the collection the client points to
+*/
+func (cf *config) scope(collection string) {
+	cf.scopedTimeout = nil
+	if t, ok := cf.collectionTimeouts[collection]; ok {
+		cf.scopedTimeout = &t
+	}
+}
@@ -0,0 +1,231 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/*
+Bits of precision of the big.Float values made from Decimal128, a bit more than its 34 digits
+*/
+const decimalPrecision = 128
+
+var (
+	tBigFloat = reflect.TypeOf((*big.Float)(nil))
+	tBigRat   = reflect.TypeOf((*big.Rat)(nil))
+)
+
+/*
+Converts a big.Float to a Decimal128, rounded to 34 significant digits
+
+	*big.Float number to convert
+
+Returns:
+
+	the decimal - primitive.Decimal128
+
+	an err - error, when the number is out of the range of Decimal128
+*/
+func DecimalFromFloat(f *big.Float) (primitive.Decimal128, error) {
+	if f.IsInf() {
+		return primitive.Decimal128{}, errors.New("infinite numbers can't be converted to Decimal128")
+	}
+	// the shortest text holding the value avoids the binary noise of the float. ex: 0.1
+	if d, err := primitive.ParseDecimal128(f.Text('g', -1)); err == nil {
+		return d, nil
+	}
+	return primitive.ParseDecimal128(f.Text('g', 34))
+}
+
+/*
+Converts a Decimal128 to a big.Float
+
+	primitive.Decimal128 decimal to convert
+
+Returns:
+
+	the number - *big.Float
+
+	an err - error, when the decimal is NaN or infinite
+*/
+func DecimalToFloat(d primitive.Decimal128) (*big.Float, error) {
+	r, err := DecimalToRat(d)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Float).SetPrec(decimalPrecision).SetRat(r), nil
+}
+
+/*
+Converts a big.Rat to a Decimal128, rounded to 34 significant digits
+
+	*big.Rat number to convert
+
+Returns:
+
+	the decimal - primitive.Decimal128
+
+	an err - error, when the number is out of the range of Decimal128
+*/
+func DecimalFromRat(r *big.Rat) (primitive.Decimal128, error) {
+	return DecimalFromFloat(new(big.Float).SetPrec(decimalPrecision).SetRat(r))
+}
+
+/*
+Converts a Decimal128 to an exact big.Rat
+
+	primitive.Decimal128 decimal to convert
+
+Returns:
+
+	the number - *big.Rat
+
+	an err - error, when the decimal is NaN or infinite
+*/
+func DecimalToRat(d primitive.Decimal128) (*big.Rat, error) {
+	coefficient, exponent, err := d.BigInt()
+	if err != nil {
+		return nil, err
+	}
+	r := new(big.Rat).SetInt(coefficient)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exponent))), nil))
+	if exponent < 0 {
+		return r.Quo(r, scale), nil
+	}
+	return r.Mul(r, scale), nil
+}
+
+/*
+Creates a Decimal128 from a coefficient and a base 10 exponent, the representation
+of decimal libraries. ex: DecimalFromParts(d.Coefficient(), int(d.Exponent())) for a shopspring decimal
+The inverse is Decimal128.BigInt
+
+	*big.Int coefficient of the decimal
+
+	int: exponent of the decimal. ex: -2 for cents
+
+Returns:
+
+	the decimal - primitive.Decimal128
+
+	an err - error, when the decimal is out of the range of Decimal128
+*/
+func DecimalFromParts(coefficient *big.Int, exponent int) (primitive.Decimal128, error) {
+	d, ok := primitive.ParseDecimal128FromBigInt(coefficient, exponent)
+	if !ok {
+		return primitive.Decimal128{}, fmt.Errorf("%se%d is out of the range of Decimal128", coefficient, exponent)
+	}
+	return d, nil
+}
+
+/*
+Stores *big.Float and *big.Rat values as Decimal128 and decodes them back
+Decoding also accepts doubles, integers and strings
+
+Returns:
+
+	an option - Option
+*/
+func WithDecimalCodec() Option {
+	return func(cf *config) {
+		cf.codecs = append(cf.codecs, registerDecimalCodecs)
+	}
+}
+
+func registerDecimalCodecs(rb *bsoncodec.RegistryBuilder) {
+	rb.RegisterTypeEncoder(tBigFloat, bsoncodec.ValueEncoderFunc(encodeBigNumber))
+	rb.RegisterTypeEncoder(tBigRat, bsoncodec.ValueEncoderFunc(encodeBigNumber))
+	rb.RegisterTypeDecoder(tBigFloat, bsoncodec.ValueDecoderFunc(decodeBigNumber))
+	rb.RegisterTypeDecoder(tBigRat, bsoncodec.ValueDecoderFunc(decodeBigNumber))
+}
+
+func encodeBigNumber(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if val.IsNil() {
+		return vw.WriteNull()
+	}
+	var d primitive.Decimal128
+	var err error
+	switch n := val.Interface().(type) {
+	case *big.Float:
+		d, err = DecimalFromFloat(n)
+	case *big.Rat:
+		d, err = DecimalFromRat(n)
+	default:
+		return bsoncodec.ValueEncoderError{Name: "BigNumberEncodeValue", Types: []reflect.Type{tBigFloat, tBigRat}, Received: val}
+	}
+	if err != nil {
+		return err
+	}
+	return vw.WriteDecimal128(d)
+}
+
+func decodeBigNumber(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || (val.Type() != tBigFloat && val.Type() != tBigRat) {
+		return bsoncodec.ValueDecoderError{Name: "BigNumberDecodeValue", Types: []reflect.Type{tBigFloat, tBigRat}, Received: val}
+	}
+	var r *big.Rat
+	switch vr.Type() {
+	case bsontype.Null:
+		val.Set(reflect.Zero(val.Type()))
+		return vr.ReadNull()
+	case bsontype.Decimal128:
+		d, err := vr.ReadDecimal128()
+		if err != nil {
+			return err
+		}
+		if r, err = DecimalToRat(d); err != nil {
+			return err
+		}
+	case bsontype.Double:
+		f, err := vr.ReadDouble()
+		if err != nil {
+			return err
+		}
+		if r = new(big.Rat).SetFloat64(f); r == nil {
+			return fmt.Errorf("%v can't be decoded into a big number", f)
+		}
+	case bsontype.Int32:
+		i, err := vr.ReadInt32()
+		if err != nil {
+			return err
+		}
+		r = new(big.Rat).SetInt64(int64(i))
+	case bsontype.Int64:
+		i, err := vr.ReadInt64()
+		if err != nil {
+			return err
+		}
+		r = new(big.Rat).SetInt64(i)
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if r, ok = new(big.Rat).SetString(s); !ok {
+			return fmt.Errorf("%q is not a number", s)
+		}
+	default:
+		return fmt.Errorf("cannot decode %v into a big number", vr.Type())
+	}
+	if val.Type() == tBigRat {
+		val.Set(reflect.ValueOf(r))
+		return nil
+	}
+	val.Set(reflect.ValueOf(new(big.Float).SetPrec(decimalPrecision).SetRat(r)))
+	return nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
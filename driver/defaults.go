@@ -0,0 +1,182 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	tObjectID = reflect.TypeOf(primitive.ObjectID{})
+	tDateTime = reflect.TypeOf(primitive.DateTime(0))
+)
+
+/*
+Defaulter interface
+Implemented by models that fill their own defaults before they are inserted,
+it is called after the default tags are applied, ex:
+func (u *User) SetDefaults() { if u.Role == "" { u.Role = "member" } }
+*/
+type Defaulter interface {
+	SetDefaults()
+}
+
+/*
+Fills the zero fields of an object before it is inserted
+Fields are filled from their default tag, then SetDefaults is called. ex:
+
+	Status string `bson:"status" default:"active"`
+
+	ID primitive.ObjectID `bson:"_id" default:"new"`: a new ObjectID
+
+	CreatedAt time.Time `bson:"createdAt" default:"now"`: the current time, also for primitive.DateTime
+
+Strings, bools and numbers take the value of the tag, pointers are set when they are nil
+A pointer to a struct is filled in place, a struct is copied first
+
+	interface{} object to insert
+
+Returns:
+
+	the object to insert - interface{}
+
+	an err - error
+*/
+func (c *Client) defaults(object interface{}) (interface{}, error) {
+	v := reflect.ValueOf(object)
+	if v.Kind() == reflect.Struct {
+		copied := reflect.New(v.Type())
+		copied.Elem().Set(v)
+		v, object = copied, copied.Interface()
+	}
+	if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		if err := fillDefaults(v.Elem(), map[reflect.Type]bool{}); err != nil {
+			return nil, err
+		}
+	}
+	if d, ok := object.(Defaulter); ok {
+		d.SetDefaults()
+	}
+	return object, nil
+}
+
+/*
+Fills the defaults of a list of objects
+*/
+func (c *Client) defaultsAll(objects []interface{}) ([]interface{}, error) {
+	filled := make([]interface{}, len(objects))
+	for i, object := range objects {
+		var err error
+		if filled[i], err = c.defaults(object); err != nil {
+			return nil, fmt.Errorf("object %d: %w", i, err)
+		}
+	}
+	return filled, nil
+}
+
+func fillDefaults(v reflect.Value, seen map[reflect.Type]bool) error {
+	t := v.Type()
+	if seen[t] { // recursive types
+		return nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		value := v.Field(i)
+		if tag, ok := field.Tag.Lookup("default"); ok {
+			if err := setDefault(value, tag); err != nil {
+				return fmt.Errorf("default tag of %s: %w", field.Name, err)
+			}
+			continue
+		}
+		// fill the fields of nested structs
+		if value.Kind() == reflect.Pointer && !value.IsNil() {
+			value = value.Elem()
+		}
+		if value.Kind() == reflect.Struct && value.Type() != tTime {
+			if err := fillDefaults(value, seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+Sets a field to the value of its default tag when it is zero
+*/
+func setDefault(field reflect.Value, tag string) error {
+	if !field.IsZero() {
+		return nil
+	}
+	if field.Kind() == reflect.Pointer {
+		value := reflect.New(field.Type().Elem())
+		if err := parseDefault(value.Elem(), tag); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+	return parseDefault(field, tag)
+}
+
+func parseDefault(field reflect.Value, tag string) error {
+	switch field.Type() {
+	case tTime:
+		if tag != "now" {
+			return errors.New("the default of a time must be now")
+		}
+		field.Set(reflect.ValueOf(Now()))
+		return nil
+	case tDateTime:
+		if tag != "now" {
+			return errors.New("the default of a date must be now")
+		}
+		field.Set(reflect.ValueOf(primitive.NewDateTimeFromTime(Now())))
+		return nil
+	case tObjectID:
+		if tag != "new" {
+			return errors.New("the default of an ObjectID must be new")
+		}
+		field.Set(reflect.ValueOf(NewID()))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(tag)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(tag)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(tag, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(tag, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(tag, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return errors.New("fields of type " + field.Type().String() + " can't have a default")
+	}
+	return nil
+}
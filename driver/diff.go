@@ -0,0 +1,177 @@
+package driver
+
+import (
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+/*
+Computes the smallest update turning a document into another one
+Changed fields of embedded documents are set one by one, arrays that changed
+are set as a whole and removed fields are unset. The _id is never part of the diff
+
+	interface{} document or struct before the change
+
+	interface{} document or struct after the change
+
+Returns:
+
+	*UpdateBuilder pointer to an update builder, empty if nothing changed
+
+	an err - error
+*/
+func Diff(old interface{}, new interface{}) (*UpdateBuilder, error) {
+	before, err := bson.Marshal(old)
+	if err != nil {
+		return nil, err
+	}
+	after, err := bson.Marshal(new)
+	if err != nil {
+		return nil, err
+	}
+	u := Update()
+	if err := diff(u, "", bson.Raw(before), bson.Raw(after)); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+/*
+Adds the changes between two documents under a path to the update
+*/
+func diff(u *UpdateBuilder, prefix string, before bson.Raw, after bson.Raw) error {
+	elements, err := after.Elements()
+	if err != nil {
+		return err
+	}
+	for _, e := range elements {
+		key := e.Key()
+		if prefix == "" && key == "_id" {
+			continue
+		}
+		value := e.Value()
+		previous, err := before.LookupErr(key)
+		switch {
+		case err != nil:
+			u.Set(prefix+key, value)
+		case previous.Type == bsontype.EmbeddedDocument && value.Type == bsontype.EmbeddedDocument:
+			if err := diff(u, prefix+key+".", previous.Document(), value.Document()); err != nil {
+				return err
+			}
+		case !previous.Equal(value):
+			u.Set(prefix+key, value)
+		}
+	}
+	removed, err := before.Elements()
+	if err != nil {
+		return err
+	}
+	for _, e := range removed {
+		if prefix == "" && e.Key() == "_id" {
+			continue
+		}
+		if _, err := after.LookupErr(e.Key()); err != nil {
+			u.Unset(prefix + e.Key())
+		}
+	}
+	return nil
+}
+
+/*
+Applies the $set and $unset of an update to a document without touching the database
+ex: to record the document an update leads to in an audit log
+
+	interface{} document or struct to apply the update to
+
+	interface{} update with only $set and $unset. ex: the result of Diff
+
+	interface{} pointer to decode the updated document into
+
+Returns:
+
+	an err - error
+*/
+func ApplyPatch(document interface{}, patch interface{}, result interface{}) error {
+	b, err := bson.Marshal(document)
+	if err != nil {
+		return err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(b, &doc); err != nil {
+		return err
+	}
+	p, err := bson.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	var ops bson.D
+	if err := bson.Unmarshal(p, &ops); err != nil {
+		return err
+	}
+	for _, op := range ops {
+		fields, ok := op.Value.(bson.D)
+		if !ok {
+			return errors.New("patch operator " + op.Key + " is not a document")
+		}
+		for _, field := range fields {
+			path := strings.Split(field.Key, ".")
+			switch op.Key {
+			case "$set":
+				doc = setPath(doc, path, field.Value)
+			case "$unset":
+				doc = unsetPath(doc, path)
+			default:
+				return errors.New("patch operator " + op.Key + " is not supported, only $set and $unset are")
+			}
+		}
+	}
+	out, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(out, result)
+}
+
+/*
+Sets the value at a dotted path, creating the missing embedded documents
+*/
+func setPath(doc bson.D, path []string, value interface{}) bson.D {
+	for i := range doc {
+		if doc[i].Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			doc[i].Value = value
+			return doc
+		}
+		sub, _ := doc[i].Value.(bson.D)
+		doc[i].Value = setPath(sub, path[1:], value)
+		return doc
+	}
+	if len(path) == 1 {
+		return append(doc, bson.E{Key: path[0], Value: value})
+	}
+	return append(doc, bson.E{Key: path[0], Value: setPath(bson.D{}, path[1:], value)})
+}
+
+/*
+Removes the value at a dotted path
+*/
+func unsetPath(doc bson.D, path []string) bson.D {
+	for i := range doc {
+		if doc[i].Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return append(doc[:i], doc[i+1:]...)
+		}
+		if sub, ok := doc[i].Value.(bson.D); ok {
+			doc[i].Value = unsetPath(sub, path[1:])
+		}
+		return doc
+	}
+	return doc
+}
@@ -2,9 +2,12 @@ package driver
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -14,10 +17,11 @@ import (
 Client Object
 */
 type Client struct {
-	cl *mongo.Client
+	hs *atomic.Pointer[handles]
 	cr *Credentials
-	db *mongo.Database
-	co *mongo.Collection
+	cf config
+	cx context.Context
+	cn *connection
 	u  string
 }
 
@@ -30,6 +34,15 @@ type Credentials struct {
 	password string
 }
 
+/*
+CredentialsProvider interface
+Fetches the username and password every time the client connects, so rotated
+credentials are picked up without restarting. See WithCredentialsProvider
+*/
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (username string, password string, err error)
+}
+
 /*
 Create a new Client object
 
@@ -37,13 +50,18 @@ Create a new Client object
 	string: password to authenticate with
 	string: url of db after the credentials. ex: @dbname.smchw.mongodb.net/test
 
+	...Option: options to configure the client with
+
 Returns:
 
 	*Client pointer to a client object
 */
-func NewClient(_username string, _password string, _url string) *Client {
+func NewClient(_username string, _password string, _url string, _options ...Option) *Client {
 	client := Client{
-		u: _url,
+		u:  _url,
+		hs: &atomic.Pointer[handles]{},
+		cf: newConfig(_options),
+		cn: &connection{},
 		cr: &Credentials{
 			username: _username,
 			password: _password,
@@ -54,6 +72,7 @@ func NewClient(_username string, _password string, _url string) *Client {
 
 /*
 Creates a Connection to the database
+Connecting again replaces the connection, the previous one is disconnected
 
 Returns:
 
@@ -62,16 +81,40 @@ Returns:
 	an err - error
 */
 func (c *Client) Connect() error {
-	var err error
+	c.cn.dial.Lock()
+	defer c.cn.dial.Unlock()
+	return c.connect()
+}
 
+/*
+Connects while holding the dial lock of the connection
+*/
+func (c *Client) connect() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	c.cl, err = mongo.Connect(ctx, options.Client().ApplyURI(`mongodb+srv://`+c.cr.username+`:`+c.cr.password+c.u))
+	c.cn.set(StateConnecting)
+	if c.cf.credentials != nil {
+		username, password, err := c.cf.credentials.Credentials(ctx)
+		if err != nil {
+			c.cn.set(StateDisconnected)
+			return err
+		}
+		c.cr = &Credentials{username: username, password: password}
+	}
+	opts := c.clientURI()
+	opts.SetServerMonitor(c.cn.serverMonitor())
+	opts.SetPoolMonitor(c.cn.poolMonitor())
+	cl, err := mongo.Connect(ctx, c.cf.clientOptions(opts))
 	if err != nil {
+		c.cn.set(StateDisconnected)
 		return err
 	}
-	return nil
+	if stale := c.cn.connected(cl, c.cf.monitor); stale != nil {
+		stale.Disconnect(ctx)
+	}
+	c.cn.set(StateConnected)
+	return c.rebind()
 }
 
 /*
@@ -84,38 +127,85 @@ Returns:
 	an err - error
 */
 func (c *Client) Disconnect() (bool, error) {
-	if err := c.cl.Disconnect(context.TODO()); err != nil {
+	cl := c.cn.client()
+	if cl == nil { // never connected
+		return true, nil
+	}
+	if err := cl.Disconnect(context.TODO()); err != nil {
 		return false, err
 	}
+	c.cn.disconnected()
+	c.bind(func(h *handles) { h.cl = nil })
 	return true, nil
 }
 
 /*
-Ping Server to make sure its connected
-We use this to make sure we are connected to the server before making any changes
+Ping Server to make sure its connected, ex: for health checks
+The client connects on first use if Connect was not called. The ping stops
+once the context is done or the timeout set with WithPingTimeout expires
 
-Returns:
+	context.Context: context of the ping
 
-	a boolean - bool
+	ReadPreference: members to ping, ReadPrimary when empty. ex: ReadNearest to check any member answers
+
+Returns:
 
 	an err - error
 */
-func (c *Client) Ping() error {
-	err := c.cl.Ping(context.TODO(), readpref.Primary())
-	if err != nil { // if ping fails
-		err = c.Connect() // try to reconnect
-		if err != nil {   // if that fails return the error
-			return err
+func (c *Client) Ping(ctx context.Context, preference ReadPreference) error {
+	if preference == "" {
+		preference = ReadPrimary
+	}
+	mode, err := readpref.ModeFromString(string(preference))
+	if err != nil {
+		return err
+	}
+	rp, err := readpref.New(mode)
+	if err != nil {
+		return err
+	}
+	if c.cf.pingTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cf.pingTimeout)
+		defer cancel()
+	}
+	if c.cn.closing() {
+		return ErrShutdown
+	}
+	if err := c.connected(); err != nil {
+		return &clientError{kind: ErrNotConnected, err: err}
+	}
+	if err := c.cl().Ping(ctx, rp); err != nil {
+		// a member other than the primary not answering doesn't mean the client is disconnected
+		if mode == readpref.PrimaryMode {
+			c.cn.set(StateReconnecting)
+			if c.cf.reconnect != nil {
+				// reconnect on a copy so the connection of the caller isn't swapped under it
+				go c.copy().reconnect(*c.cf.reconnect)
+			}
 		}
+		return &clientError{kind: ErrNotConnected, err: err}
 	}
-	return err
+	c.cn.set(StateConnected)
+	return nil
+}
+
+/*
+Pings the primary to make sure the client is connected before running an operation
+*/
+func (c *Client) ping() error {
+	return c.Ping(context.Background(), ReadPrimary)
 }
 
 /*
 Sets the database we want to access
 */
 func (c *Client) SetDatabase(db_name string) {
-	c.db = c.cl.Database(db_name)
+	// connect on first use, Ping reports the error if it fails
+	if err := c.connected(); err != nil {
+		return
+	}
+	c.bind(func(h *handles) { h.db = h.cl.Database(db_name, c.cf.databaseOptions()) })
 }
 
 /*
@@ -129,14 +219,152 @@ Returns:
 	an err - error
 */
 func (c *Client) SetCollection(cl_name string) (bool, error) {
-	if c.db == nil {
-		return false, errors.New("please set a database before setting a collection")
+	if c.db() == nil {
+		return false, noDatabase("setting a collection")
 	} else {
-		c.co = c.db.Collection(cl_name)
+		c.cf.scope(cl_name)
+		c.bind(func(h *handles) { h.co = h.db.Collection(cl_name, c.cf.collectionOptions()) })
 		return true, nil
 	}
 }
 
+/*
+Context used to run operations
+Clients bound to a session carry the session context
+*/
+func (c *Client) context() context.Context {
+	if c.cx != nil {
+		return c.cx
+	}
+	return context.Background()
+}
+
+/*
+Context used to run a single operation
+It expires after the timeout of the call, the collection or the client,
+or DefaultTimeout when none is set
+*/
+func (c *Client) operation() (context.Context, context.CancelFunc) {
+	c.cn.begin()
+	ctx, cancel := c.step(c.context())
+	return ctx, func() {
+		cancel()
+		c.cn.end()
+	}
+}
+
+/*
+Context used to run an operation streaming a cursor, ex: an export
+Shutdown waits for it like for a single operation but it has no deadline,
+each call to the server gets its own with step. The cancel function can be
+called more than once
+*/
+func (c *Client) stream() (context.Context, context.CancelFunc) {
+	c.cn.begin()
+	ctx, cancel := context.WithCancel(c.context())
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			cancel()
+			c.cn.end()
+		})
+	}
+}
+
+/*
+Context used to run a single call of a stream, ex: fetching the next batch
+It expires like the context of an operation
+*/
+func (c *Client) step(parent context.Context) (context.Context, context.CancelFunc) {
+	timeout, ok := c.cf.deadline()
+	if !ok {
+		timeout = DefaultTimeout
+	}
+	if timeout > 0 {
+		return context.WithTimeout(parent, timeout)
+	}
+	return context.WithCancel(parent)
+}
+
+/*
+Moves a cursor of a stream to its next document, a batch fetched by it is bounded by step
+*/
+func (c *Client) next(ctx context.Context, cursor *mongo.Cursor) bool {
+	ctx, cancel := c.step(ctx)
+	defer cancel()
+	return cursor.Next(ctx)
+}
+
+/*
+Runs the first call of a stream, bounded by step
+*/
+func (c *Client) first(ctx context.Context, call func(context.Context) error) error {
+	ctx, cancel := c.step(ctx)
+	defer cancel()
+	return call(ctx)
+}
+
+/*
+Creates a copy of the client with some of its options overridden
+The copy shares the connection with the original client so it is
+cheap to create one for a single operation. ex: c.With(WithReadConcern(ReadConcernMajority)).FindOne(filter)
+
+	...Option: options to override
+
+Returns:
+
+	*Client pointer to a client object
+*/
+func (c *Client) With(_options ...Option) *Client {
+	client := c.copy()
+	timeout := client.cf.timeout
+	client.cf.apply(_options)
+	// a timeout set on the copy is the timeout of the call, it beats the one of the collection
+	if client.cf.timeout != timeout {
+		client.cf.callTimeout = true
+	}
+	if client.db() != nil {
+		client.SetDatabase(client.db().Name())
+	}
+	if client.co() != nil {
+		client.SetCollection(client.co().Name())
+	}
+	return client
+}
+
+/*
+Creates a copy of the client on another database and collection
+Like With the copy shares the connection with the original client, use it
+to give each module of a service its own defaults. ex: c.Clone("billing", "invoices", WithWriteConcern(WriteConcern{Majority: true}))
+
+	string: database of the copy, empty to keep the one that is set
+
+	string: collection of the copy, empty to keep the one that is set
+
+	...Option: options to override
+
+Returns:
+
+	*Client pointer to a client object
+
+	an err - error
+*/
+func (c *Client) Clone(database string, collection string, _options ...Option) (*Client, error) {
+	client := c.With(_options...)
+	if database != "" {
+		client.SetDatabase(database)
+		if client.co() != nil && collection == "" {
+			collection = client.co().Name()
+		}
+	}
+	if collection != "" {
+		if _, err := client.SetCollection(collection); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
 /*
 Finds an object from the collection using a filter and returns it
 
@@ -146,13 +374,29 @@ Returns:
 
 	an interface object - interface{}
 */
-func (c *Client) FindOne(filter interface{}) *mongo.SingleResult {
+func (c *Client) FindOne(filter interface{}) (res *mongo.SingleResult) {
+	defer c.observeResult("findOne", time.Now(), &res)
+	if err := c.collection("finding an object"); err != nil {
+		return errorResult(err)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
 	// ping database
-	if err := c.Ping(); err != nil {
-		return nil
+	if err := c.ping(); err != nil {
+		return errorResult(err)
 	}
 
-	return c.co.FindOne(context.Background(), filter)
+	res = c.findOne(ctx, filter)
+	if err := res.Err(); err != mongo.ErrNoDocuments {
+		c.retry(0, err, func() error {
+			res = c.findOne(ctx, filter)
+			if err := res.Err(); err != mongo.ErrNoDocuments {
+				return err
+			}
+			return nil
+		})
+	}
+	return c.redactResult(res)
 }
 
 /*
@@ -167,48 +411,272 @@ Returns:
 	an array of interfaces - []interface{}
 */
 func (c *Client) FindMany(filter interface{}, options *options.FindOptions) *mongo.Cursor {
+	var err error
+	defer c.observe("find", time.Now(), &err)
+	if err = c.collection("finding objects"); err != nil {
+		return nil
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
 	// ping database
-	if err := c.Ping(); err != nil {
+	if err = c.ping(); err != nil {
 		return nil
 	}
 
-	cursor, err := c.co.Find(context.Background(), filter, options)
+	cursor, err := c.co().Find(ctx, filter, c.cf.findOptions(), options)
+	err = c.retry(0, err, func() (err error) {
+		cursor, err = c.co().Find(ctx, filter, c.cf.findOptions(), options)
+		return err
+	})
 	// if there is an error return nil
 	if err != nil {
 		return nil
 	}
+	cursor, err = c.redactCursor(ctx, cursor)
+	if err != nil {
+		return nil
+	}
 	return cursor
 }
 
 /*
-Insert one object into the collection and return the object
+Finds many objects by a filter in the collection and decodes them into results
 
-	interface{} object to insert in collection
+	interface{} filter to query objects by
 
-	interface{} options to inserting into the collection
+	interface{} pointer to a slice to decode the objects into
+
+	interface{} options to query collection with
 
 Returns:
 
-	an object - interface{}
+	an err - error
 */
-func (c *Client) InsertOne(object interface{}, options *options.InsertOneOptions) any {
+func (c *Client) FindAll(filter interface{}, results interface{}, options *options.FindOptions) (err error) {
+	defer c.observe("find", time.Now(), &err)
+	if err := c.collection("finding objects"); err != nil {
+		return err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
 	// ping database
-	if err := c.Ping(); err != nil {
+	if err := c.ping(); err != nil {
+		return err
+	}
+
+	cursor, err := c.co().Find(ctx, filter, c.cf.findOptions(), options)
+	err = c.retry(0, err, func() (err error) {
+		cursor, err = c.co().Find(ctx, filter, c.cf.findOptions(), options)
+		return err
+	})
+	if err != nil {
+		return mapError(err)
+	}
+	return c.all(ctx, cursor, results)
+}
+
+/*
+Finds the distinct values of a field among the objects matching a filter
+The values of a redacted field are refused, see WithRedaction
+
+	string: field to get the values of
+
+	interface{} filter to query objects by
+
+Returns:
+
+	the values - []interface{}
+
+	an err - error
+*/
+func (c *Client) Distinct(field string, filter interface{}) (_ []interface{}, err error) {
+	defer c.observe("distinct", time.Now(), &err)
+	if err := c.collection("finding distinct values"); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return nil, err
+	}
+	values, err := c.co().Distinct(ctx, field, filter, c.cf.distinctOptions())
+	err = c.retry(0, err, func() (err error) {
+		values, err = c.co().Distinct(ctx, field, filter, c.cf.distinctOptions())
+		return err
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return c.redactDistinct(field, values)
+}
+
+/*
+Counts the objects matching a filter in the collection
+
+	interface{} filter to query objects by, nil for every object
+
+Returns:
+
+	the number of objects - int64
+
+	an err - error
+*/
+func (c *Client) Count(filter interface{}) (_ int64, err error) {
+	defer c.observe("count", time.Now(), &err)
+	if err := c.collection("counting objects"); err != nil {
+		return 0, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return 0, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	return c.count(ctx, filter)
+}
+
+/*
+Runs an aggregation pipeline on the collection and returns the results
+A pipeline ending with $out or $merge is a write, read-only clients refuse it
+and dry-run clients report it, see MaterializeTo and MergeTo
+
+	interface{} pipeline to run, ex: Pipeline().Match(filter).Stages()
+
+	interface{} options to run the aggregation with
+
+Returns:
+
+	a cursor over the results - *mongo.Cursor
+*/
+func (c *Client) Aggregate(pipeline interface{}, options *options.AggregateOptions) *mongo.Cursor {
+	var err error
+	defer c.observe("aggregate", time.Now(), &err)
+	if err = c.collection("running an aggregation"); err != nil {
 		return nil
 	}
-	_, err := c.co.InsertOne(context.Background(), object, options)
-	if err != nil { // we try again
-		_, err := c.co.InsertOne(context.Background(), object, options)
-		if err != nil {
-			return err
+	target, err := c.writeTarget(pipeline)
+	if err != nil {
+		return nil
+	}
+	if target != "" {
+		if err = c.writable(); err != nil {
+			return nil
 		}
+		defer c.invalidateNamespace(target)
 	}
-	return object
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err = c.ping(); err != nil {
+		return nil
+	}
+	if target != "" && c.cf.dryRun != nil {
+		if _, err = c.dryRun(ctx, "aggregate", nil, pipeline, false); err == nil {
+			err = ErrDryRun
+		}
+		return nil
+	}
+
+	cursor, err := c.co().Aggregate(ctx, pipeline, c.cf.aggregateOptions(), options)
+	// if there is an error return nil
+	if err != nil {
+		return nil
+	}
+	cursor, err = c.redactCursor(ctx, cursor)
+	if err != nil {
+		return nil
+	}
+	return cursor
 }
 
 /*
-TODO: NOT IMPLEMENTED
-Insert one object into the collection and return the object
+Insert one object into the collection
+An _id is generated for objects without one
+
+	interface{} object to insert in collection
+
+	interface{} options to inserting into the collection
+
+Returns:
+
+	the _id of the object - *InsertResult
+
+	an err - error
+*/
+func (c *Client) InsertOne(object interface{}, options *options.InsertOneOptions) (_ *InsertResult, err error) {
+	defer c.observe("insertOne", time.Now(), &err)
+	if err := c.collection("inserting an object"); err != nil {
+		return nil, err
+	}
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	object, err = c.prepare(object)
+	if err != nil {
+		return nil, err
+	}
+	id, err := c.insertOne(object, options)
+	if err != nil {
+		return nil, err
+	}
+	return &InsertResult{InsertedID: id, InsertedIDs: []interface{}{id}}, nil
+}
+
+/*
+Fills the defaults of an object about to be inserted and validates it
+*/
+func (c *Client) prepare(object interface{}) (interface{}, error) {
+	object, err := c.defaults(object)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.validate(object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+/*
+Inserts one object prepared with prepare, retrying once if it fails unless WithRetries says otherwise
+*/
+func (c *Client) insertOne(object interface{}, options *options.InsertOneOptions) (interface{}, error) {
+	defer c.invalidate()
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return nil, err
+	}
+	// pin the _id so that retrying can't insert the object twice
+	doc, id, _, err := withID(c.cf.codecRegistry(), object)
+	if err != nil {
+		return nil, err
+	}
+	if c.cf.dryRun != nil {
+		_, err := c.dryRun(ctx, "insertOne", nil, doc, true)
+		return id, err
+	}
+	if doc, err = c.fit(doc); err != nil {
+		return nil, err
+	}
+	_, err = c.co().InsertOne(ctx, doc, options)
+	err = c.retry(1, err, func() error { // we try again
+		_, err := c.co().InsertOne(ctx, doc, options)
+		if c.inserted(ctx, err, id) { // a previous attempt went through
+			return nil
+		}
+		return err
+	})
+	return id, mapError(err)
+}
+
+/*
+Insert many objects into the collection
+An _id is generated for objects without one
 
 	interface{} objects to insert in collection
 
@@ -216,12 +684,98 @@ Insert one object into the collection and return the object
 
 Returns:
 
-	an object - interface{}
+	the _id of the objects - *InsertResult
+
+	an err - error
 */
-func (c *Client) InsertMany(objects []interface{}, options *options.InsertManyOptions) any {
+func (c *Client) InsertMany(objects []interface{}, options *options.InsertManyOptions) (_ *InsertResult, err error) {
+	defer c.observe("insertMany", time.Now(), &err)
+	if err := c.collection("inserting objects"); err != nil {
+		return nil, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	docs, ids, err := c.prepareInserts(objects)
+	if err != nil {
+		return nil, err
+	}
 	// ping database
-	if err := c.Ping(); err != nil {
-		return nil
+	if err := c.ping(); err != nil {
+		return nil, err
+	}
+	if c.cf.dryRun != nil {
+		if _, err := c.dryRun(ctx, "insertMany", nil, docs, false); err != nil {
+			return nil, err
+		}
+		return &InsertResult{InsertedID: firstID(ids), InsertedIDs: ids}, nil
+	}
+	if err := c.fitAll(docs); err != nil {
+		return nil, err
+	}
+	_, err = c.co().InsertMany(ctx, docs, options)
+	err = c.retry(1, err, func() error { // we try again
+		_, err := c.co().InsertMany(ctx, docs, options)
+		if mongo.IsDuplicateKeyError(err) { // some objects went through a previous attempt
+			n, cerr := c.co().CountDocuments(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}, c.cf.countOptions())
+			if cerr == nil && n == int64(len(ids)) {
+				return nil
+			}
+		}
+		return err
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &InsertResult{InsertedID: firstID(ids), InsertedIDs: ids}, nil
+}
+
+/*
+Fills the defaults of objects about to be inserted, validates them and pins their _ids
+so that retrying can't insert the objects twice
+
+	[]interface{} objects to insert
+
+Returns:
+
+	the documents to insert - []interface{}
+
+	the _id of the documents - []interface{}
+
+	an err - error
+*/
+func (c *Client) prepareInserts(objects []interface{}) ([]interface{}, []interface{}, error) {
+	objects, err := c.defaultsAll(objects)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.validateAll(objects); err != nil {
+		return nil, nil, err
+	}
+	docs := make([]interface{}, len(objects))
+	ids := make([]interface{}, len(objects))
+	for i, object := range objects {
+		doc, id, _, err := withID(c.cf.codecRegistry(), object)
+		if err != nil {
+			return nil, nil, err
+		}
+		docs[i], ids[i] = doc, id
+	}
+	return docs, ids, nil
+}
+
+/*
+Applies the size guard to documents about to be inserted
+*/
+func (c *Client) fitAll(docs []interface{}) error {
+	for i := range docs {
+		var err error
+		if docs[i], err = c.fit(docs[i]); err != nil {
+			return fmt.Errorf("object %d: %w", i, err)
+		}
 	}
 	return nil
 }
@@ -237,124 +791,295 @@ Update one object from the collection
 
 Returns:
 
-	the updated object - interface{}
+	the counts of the update - *UpdateResult
+
+	an err - error
 */
-func (c *Client) UpdateOne(filter interface{}, update interface{}, options *options.UpdateOptions) *mongo.SingleResult {
+func (c *Client) UpdateOne(filter interface{}, update interface{}, options *options.UpdateOptions) (_ *UpdateResult, err error) {
+	defer c.observe("updateOne", time.Now(), &err)
+	if err := c.collection("updating an object"); err != nil {
+		return nil, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	if err := ValidateUpdate(update); err != nil {
+		return nil, err
+	}
 	// ping database
-	if err := c.Ping(); err != nil {
-		return nil
+	if err := c.ping(); err != nil {
+		return nil, err
 	}
-	_, err := c.co.UpdateOne(context.Background(), filter, update, options)
-	if err != nil { // try again
-		_, err := c.co.UpdateOne(context.Background(), filter, update, options)
+	if c.cf.dryRun != nil {
+		matched, err := c.dryRun(ctx, "updateOne", filter, update, true)
 		if err != nil {
-			return nil
+			return nil, err
 		}
+		return &UpdateResult{Matched: matched}, nil
 	}
-	return c.FindOne(filter)
+	res, err := c.co().UpdateOne(ctx, filter, update, c.cf.updateOptions(), options)
+	err = c.retry(1, err, func() (err error) { // try again
+		res, err = c.co().UpdateOne(ctx, filter, update, c.cf.updateOptions(), options)
+		return err
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return updateResult(res), nil
 }
 
 /*
-Update one object from the collection
+Update many objects from the collection
 
 	interface{} filter to query objects by
 
-	interface{} update changes to made to the document
+	interface{} update changes to made to the documents
 
 	interface{} options to update the collection with
 
 Returns:
 
-	the updated object - interface{}
+	the counts of the update - *UpdateResult
+
+	an err - error
 */
-func (c *Client) UpdateMany(filter interface{}, updates interface{}, options *options.UpdateOptions) any {
+func (c *Client) UpdateMany(filter interface{}, updates interface{}, options *options.UpdateOptions) (_ *UpdateResult, err error) {
+	defer c.observe("updateMany", time.Now(), &err)
+	if err := c.collection("updating objects"); err != nil {
+		return nil, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	if err := ValidateUpdate(updates); err != nil {
+		return nil, err
+	}
 	// ping database
-	if err := c.Ping(); err != nil {
-		return nil
+	if err := c.ping(); err != nil {
+		return nil, err
 	}
-	return nil
+	if c.cf.dryRun != nil {
+		matched, err := c.dryRun(ctx, "updateMany", filter, updates, false)
+		if err != nil {
+			return nil, err
+		}
+		return &UpdateResult{Matched: matched}, nil
+	}
+	res, err := c.co().UpdateMany(ctx, filter, updates, c.cf.updateOptions(), options)
+	err = c.retry(1, err, func() (err error) { // try again
+		res, err = c.co().UpdateMany(ctx, filter, updates, c.cf.updateOptions(), options)
+		return err
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return updateResult(res), nil
 }
 
 /*
-Remove one object from the collection
+Updates one object from the collection and returns it in a single operation
+It is not retried as the update may have been applied, use it to claim or
+increment documents atomically
 
 	interface{} filter to query object by
 
-	interface{} options to delete object from the collection with
+	interface{} update changes to made to the document
 
-	returns a boolean if successful
+	interface{} options to update the collection with, ex: options.FindOneAndUpdate().SetReturnDocument(options.After)
 
 Returns:
 
-	boolean - bool
+	the object before the update, or after it if set in the options - *mongo.SingleResult, ErrDryRun in dry-run mode
 */
-func (c *Client) RemoveOne(filter interface{}, options *options.DeleteOptions) bool {
+func (c *Client) FindOneAndUpdate(filter interface{}, update interface{}, options *options.FindOneAndUpdateOptions) (res *mongo.SingleResult) {
+	defer c.observeResult("findOneAndUpdate", time.Now(), &res)
+	if err := c.collection("updating an object"); err != nil {
+		return errorResult(err)
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return errorResult(err)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	if err := ValidateUpdate(update); err != nil {
+		return errorResult(err)
+	}
 	// ping database
-	if err := c.Ping(); err != nil {
-		return false
+	if err := c.ping(); err != nil {
+		return errorResult(err)
 	}
-	_, err := c.co.DeleteOne(context.Background(), filter, options)
-	if err != nil { // try again
-		_, err := c.co.DeleteOne(context.Background(), filter, options)
-		if err != nil {
-			return false
+	if c.cf.dryRun != nil {
+		matched, err := c.dryRun(ctx, "findOneAndUpdate", filter, update, true)
+		if err == nil {
+			err = ErrDryRun
+			if matched == 0 {
+				err = ErrNotFound
+			}
 		}
+		return errorResult(err)
 	}
-	return true
+	return c.redactResult(c.co().FindOneAndUpdate(ctx, filter, update, c.cf.findOneAndUpdateOptions(), options))
 }
 
 /*
 Remove one object from the collection
 
-	interface{} filter to query objects by
+	interface{} filter to query object by
 
 	interface{} options to delete object from the collection with
 
-	returns a boolean if successful
-
 Returns:
 
-	boolean - bool
+	the count of deleted objects - *DeleteResult
+
+	an err - error
 */
-func (c *Client) RemoveMany(filter interface{}, options *options.DeleteOptions) bool {
+func (c *Client) RemoveOne(filter interface{}, options *options.DeleteOptions) (_ *DeleteResult, err error) {
+	defer c.observe("deleteOne", time.Now(), &err)
+	if err := c.collection("removing an object"); err != nil {
+		return nil, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
 	// ping database
-	if err := c.Ping(); err != nil {
-		return false
+	if err := c.ping(); err != nil {
+		return nil, err
 	}
-	_, err := c.co.DeleteMany(context.Background(), filter, options)
-	if err != nil { // try again
-		_, err := c.co.DeleteMany(context.Background(), filter, options)
+	if c.cf.dryRun != nil {
+		_, err := c.dryRun(ctx, "deleteOne", filter, nil, true)
 		if err != nil {
-			return false
+			return nil, err
 		}
+		return &DeleteResult{}, nil
 	}
-	return true
+	res, err := c.co().DeleteOne(ctx, filter, c.cf.deleteOptions(), options)
+	err = c.retry(1, err, func() (err error) { // try again
+		res, err = c.co().DeleteOne(ctx, filter, c.cf.deleteOptions(), options)
+		return err
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &DeleteResult{Deleted: res.DeletedCount}, nil
 }
 
 /*
-Remove one object from the collection
+Remove many objects from the collection
 
 	interface{} filter to query objects by
 
 	interface{} options to delete object from the collection with
 
-	returns a boolean if successful
+Returns:
+
+	the count of deleted objects - *DeleteResult
+
+	an err - error
+*/
+func (c *Client) RemoveMany(filter interface{}, options *options.DeleteOptions) (_ *DeleteResult, err error) {
+	defer c.observe("deleteMany", time.Now(), &err)
+	if err := c.collection("removing objects"); err != nil {
+		return nil, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return nil, err
+	}
+	if c.cf.dryRun != nil {
+		_, err := c.dryRun(ctx, "deleteMany", filter, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		return &DeleteResult{}, nil
+	}
+	res, err := c.co().DeleteMany(ctx, filter, c.cf.deleteOptions(), options)
+	err = c.retry(1, err, func() (err error) { // try again
+		res, err = c.co().DeleteMany(ctx, filter, c.cf.deleteOptions(), options)
+		return err
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return &DeleteResult{Deleted: res.DeletedCount}, nil
+}
+
+/*
+Replace one object from the collection
+
+	interface{} filter to query object by
+
+	interface{} replacement object to replace the document with
+
+	interface{} options to replace the document with
 
 Returns:
 
-	boolean - bool
+	the counts of the replacement - *UpdateResult
+
+	an err - error
 */
-func (c *Client) ReplaceOne(filter interface{}, replacement interface{}, options *options.ReplaceOptions) *mongo.SingleResult {
+func (c *Client) ReplaceOne(filter interface{}, replacement interface{}, options *options.ReplaceOptions) (_ *UpdateResult, err error) {
+	defer c.observe("replaceOne", time.Now(), &err)
+	if err := c.collection("replacing an object"); err != nil {
+		return nil, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	if err := ValidateReplacement(replacement); err != nil {
+		return nil, err
+	}
+	if err := c.validate(replacement); err != nil {
+		return nil, err
+	}
 	// ping database
-	if err := c.Ping(); err != nil {
-		return nil
+	if err := c.ping(); err != nil {
+		return nil, err
 	}
-	_, err := c.co.ReplaceOne(context.Background(), filter, replacement, options)
-	if err != nil { // try again
-		_, err := c.co.ReplaceOne(context.Background(), filter, replacement, options)
+	if c.cf.dryRun != nil {
+		matched, err := c.dryRun(ctx, "replaceOne", filter, replacement, true)
 		if err != nil {
-			return nil
+			return nil, err
 		}
+		return &UpdateResult{Matched: matched}, nil
 	}
-	return c.FindOne(filter)
+	if replacement, err = c.fit(replacement); err != nil {
+		return nil, err
+	}
+	res, err := c.co().ReplaceOne(ctx, filter, replacement, c.cf.replaceOptions(), options)
+	err = c.retry(1, err, func() (err error) { // try again
+		res, err = c.co().ReplaceOne(ctx, filter, replacement, c.cf.replaceOptions(), options)
+		return err
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return updateResult(res), nil
+}
+
+/*
+Creates a result that only carries an error
+*/
+func errorResult(err error) *mongo.SingleResult {
+	return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
 }
@@ -0,0 +1,122 @@
+package driver
+
+import (
+	"context"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+DryRunWrite object
+A write that would have been made by a client in dry-run mode
+
+	Operation: name of the write. ex: "updateMany"
+
+	Filter: filter of the write, nil for inserts
+
+	Document: object inserted, update or replacement
+
+	Matched: number of documents the filter matches
+*/
+type DryRunWrite struct {
+	Operation string
+	Filter    interface{}
+	Document  interface{}
+	Matched   int64
+}
+
+/*
+Runs the inserts, updates, replacements and deletes of the client in dry-run mode
+They are validated and the documents their filter matches are counted, then
+reported instead of being executed. The results hold the ids objects would
+have been inserted with and the matched counts, nothing is modified or deleted.
+Writes returning what they wrote, like FindOneAndUpdate and NextSequence, are
+reported and fail with ErrDryRun, ErrNotFound when nothing matches. So do the
+changes of the deployment, like dropping a collection or creating an index.
+Watchers don't save their resume tokens
+
+	func(DryRunWrite): receives each write, nil to log them
+
+Returns:
+
+	an option - Option
+*/
+func WithDryRun(report func(DryRunWrite)) Option {
+	return func(cf *config) {
+		if report == nil {
+			report = func(w DryRunWrite) {
+				log.Printf("dry run: %s filter=%s document=%s matched=%d", w.Operation, extJSON(w.Filter), extJSON(w.Document), w.Matched)
+			}
+		}
+		cf.dryRun = report
+	}
+}
+
+/*
+Reports a write instead of executing it
+
+	context.Context context of the operation
+
+	string: name of the write
+
+	interface{} filter of the write, nil for inserts
+
+	interface{} object inserted, update or replacement
+
+	bool: whether the write only touches one document
+
+Returns:
+
+	the number of documents the filter matches - int64
+
+	an err - error
+*/
+func (c *Client) dryRun(ctx context.Context, operation string, filter interface{}, document interface{}, one bool) (int64, error) {
+	var matched int64
+	if filter != nil {
+		opts := options.Count()
+		if one {
+			opts.SetLimit(1)
+		}
+		n, err := c.co().CountDocuments(ctx, filter, c.cf.countOptions(), opts)
+		if err != nil {
+			return 0, mapError(err)
+		}
+		matched = n
+	}
+	c.cf.dryRun(DryRunWrite{Operation: operation, Filter: filter, Document: document, Matched: matched})
+	return matched, nil
+}
+
+/*
+Reports a change of the deployment instead of making it, ex: dropping a collection
+A change can't be simulated so ErrDryRun is returned
+
+	string: name of the change
+
+	interface{} command or definition of the change
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) dryRunChange(operation string, change interface{}) error {
+	c.cf.dryRun(DryRunWrite{Operation: operation, Document: change})
+	return ErrDryRun
+}
+
+/*
+Relaxed extended JSON of a value for the logs
+*/
+func extJSON(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	b, err := bson.MarshalExtJSON(v, false, false)
+	if err != nil {
+		return "?"
+	}
+	return string(b)
+}
@@ -0,0 +1,302 @@
+package driver
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Metadata written next to a dump, in the format of mongodump .metadata.json files
+*/
+type dumpMetadata struct {
+	Options        bson.Raw   `bson:"options"`
+	Indexes        []bson.Raw `bson:"indexes"`
+	UUID           string     `bson:"uuid,omitempty"`
+	CollectionName string     `bson:"collectionName"`
+	Type           string     `bson:"type"`
+}
+
+/*
+Dumps the collection that is set as raw BSON
+The data is a sequence of BSON documents and the metadata holds the
+collection options and index definitions as extended JSON, like the
+.bson and .metadata.json files written by mongodump. The fields moved
+to GridFS are put back. Documents aren't redacted, a dump holds the
+stored values so Restore doesn't write masks over them.
+The dump isn't bounded by the timeout of the client, fetching each batch is
+
+	io.Writer writer for the documents
+
+	io.Writer writer for the metadata, nil to skip it
+
+Returns:
+
+	the number of dumped documents - int
+
+	an err - error
+*/
+func (c *Client) Dump(data io.Writer, metadata io.Writer) (_ int, err error) {
+	defer c.observe("dump", time.Now(), &err)
+	if err := c.collection("dumping it"); err != nil {
+		return 0, err
+	}
+	ctx, cancel := c.stream()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return 0, err
+	}
+	if metadata != nil {
+		if err := c.first(ctx, func(ctx context.Context) error { return c.dumpMetadata(ctx, metadata) }); err != nil {
+			return 0, mapError(err)
+		}
+	}
+
+	var cursor *mongo.Cursor
+	err = c.first(ctx, func(ctx context.Context) (err error) {
+		cursor, err = c.co().Find(ctx, bson.D{}, options.Find().SetSort(Sort().Asc("_id")))
+		return err
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+	w := bufio.NewWriter(data)
+	overflow := c.cf.size != nil && c.cf.size.Policy == SizeOverflow
+	n := 0
+	for c.next(ctx, cursor) {
+		doc := cursor.Current
+		if overflow {
+			if doc, err = c.rehydrate(doc); err != nil {
+				return n, err
+			}
+		}
+		if _, err := w.Write(doc); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := cursor.Err(); err != nil {
+		return n, mapError(err)
+	}
+	return n, w.Flush()
+}
+
+func (c *Client) dumpMetadata(ctx context.Context, w io.Writer) error {
+	specs, err := c.db().ListCollectionSpecifications(ctx, bson.D{{Key: "name", Value: c.co().Name()}})
+	if err != nil {
+		return err
+	}
+	meta := dumpMetadata{Options: bson.Raw{5, 0, 0, 0, 0}, CollectionName: c.co().Name(), Type: "collection"}
+	if len(specs) > 0 {
+		if specs[0].Options != nil {
+			meta.Options = specs[0].Options
+		}
+		meta.Type = specs[0].Type
+		if specs[0].UUID != nil {
+			meta.UUID = hex.EncodeToString(specs[0].UUID.Data)
+		}
+	}
+	cursor, err := c.co().Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+	if err := cursor.All(ctx, &meta.Indexes); err != nil {
+		return err
+	}
+	b, err := bson.MarshalExtJSON(meta, true, false)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+/*
+Restores a dump into the collection that is set
+The collection is created with the options of the metadata if it doesn't
+exist and its indexes are created after the documents are inserted.
+Documents go through the size guard, so the ones Dump put back together are moved to GridFS again.
+In dry-run mode the batches and the drop are reported, the collection and its indexes aren't created.
+Like Dump it isn't bounded by the timeout of the client, each call to the server is
+
+	io.Reader reader for the documents
+
+	io.Reader reader for the metadata, nil to skip it
+
+	bool: drop the collection before restoring
+
+Returns:
+
+	the number of restored documents - int
+
+	an err - error
+*/
+func (c *Client) Restore(data io.Reader, metadata io.Reader, drop bool) (_ int, err error) {
+	defer c.observe("restore", time.Now(), &err)
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return 0, err
+	}
+	if err := c.collection("restoring it"); err != nil {
+		return 0, err
+	}
+	ctx, cancel := c.stream()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return 0, err
+	}
+	var meta dumpMetadata
+	if metadata != nil {
+		b, err := io.ReadAll(metadata)
+		if err != nil {
+			return 0, err
+		}
+		if err := bson.UnmarshalExtJSON(b, true, &meta); err != nil {
+			return 0, err
+		}
+	}
+	dryRun := c.cf.dryRun != nil
+	if dryRun && drop {
+		c.cf.dryRun(DryRunWrite{Operation: "drop"})
+	}
+	if drop && !dryRun {
+		if err := c.first(ctx, func(ctx context.Context) error { return c.co().Drop(ctx) }); err != nil {
+			return 0, mapError(err)
+		}
+	}
+	if !dryRun {
+		if err := c.first(ctx, func(ctx context.Context) error { return c.restoreCollection(ctx, meta) }); err != nil {
+			return 0, mapError(err)
+		}
+	}
+
+	r := bufio.NewReader(data)
+	n := 0
+	batch := make([]interface{}, 0, 1000)
+	insert := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		defer func() { batch = batch[:0] }()
+		ctx, cancel := c.step(ctx)
+		defer cancel()
+		if dryRun {
+			if _, err := c.dryRun(ctx, "restore", nil, batch, false); err != nil {
+				return err
+			}
+			n += len(batch)
+			return nil
+		}
+		res, err := c.co().InsertMany(ctx, batch, options.InsertMany().SetOrdered(false))
+		if res != nil {
+			n += len(res.InsertedIDs)
+		}
+		return mapError(err)
+	}
+	limit := maxBSONSize
+	if c.cf.size != nil && c.cf.size.Policy == SizeOverflow {
+		limit = math.MaxInt32 // the size guard makes them fit
+	}
+	for {
+		doc, err := readDocument(r, limit)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return n, err
+		}
+		fitted, err := c.fit(doc)
+		if err != nil {
+			return n, err
+		}
+		batch = append(batch, fitted)
+		if len(batch) == cap(batch) {
+			if err := insert(); err != nil {
+				return n, err
+			}
+		}
+	}
+	if err := insert(); err != nil {
+		return n, err
+	}
+	if dryRun {
+		return n, nil
+	}
+	err = c.first(ctx, func(ctx context.Context) error { return c.restoreIndexes(ctx, meta.Indexes) })
+	return n, mapError(err)
+}
+
+func (c *Client) restoreCollection(ctx context.Context, meta dumpMetadata) error {
+	exists, err := c.CollectionExists(c.co().Name())
+	if err != nil || exists {
+		return err
+	}
+	cmd := bson.D{{Key: "create", Value: c.co().Name()}}
+	elements, _ := meta.Options.Elements()
+	for _, e := range elements {
+		cmd = append(cmd, bson.E{Key: e.Key(), Value: e.Value()})
+	}
+	return c.db().RunCommand(ctx, cmd).Err()
+}
+
+func (c *Client) restoreIndexes(ctx context.Context, indexes []bson.Raw) error {
+	specs := bson.A{}
+	for _, index := range indexes {
+		if index.Lookup("name").StringValue() == "_id_" {
+			continue
+		}
+		spec := bson.D{}
+		elements, err := index.Elements()
+		if err != nil {
+			return err
+		}
+		for _, e := range elements {
+			if e.Key() != "v" && e.Key() != "ns" {
+				spec = append(spec, bson.E{Key: e.Key(), Value: e.Value()})
+			}
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+	return c.db().RunCommand(ctx, bson.D{
+		{Key: "createIndexes", Value: c.co().Name()},
+		{Key: "indexes", Value: specs},
+	}).Err()
+}
+
+/*
+Reads the next length prefixed BSON document of a stream
+*/
+func readDocument(r io.Reader, limit int) (bson.Raw, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	length := int(binary.LittleEndian.Uint32(size[:]))
+	if length < 5 || length > limit {
+		return nil, errors.New("invalid document length in dump")
+	}
+	doc := make([]byte, length)
+	copy(doc, size[:])
+	if _, err := io.ReadFull(r, doc[4:]); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,191 @@
+package driver
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Encryption algorithms of explicitly encrypted fields
+*/
+const (
+	AlgorithmDeterministic = "AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic" // equal values give equal ciphertexts so they can be queried
+	AlgorithmRandom        = "AEAD_AES_256_CBC_HMAC_SHA_512-Random"        // stronger but can't be queried
+)
+
+/*
+AutoEncryption object
+Configures Client-Side Field Level Encryption
+Encryption needs the client to be built with the cse build tag and libmongocrypt
+
+	KMSProviders: credentials of the key management services by name. ex: {"local": {"key": masterKey}}
+
+	KeyVaultNamespace: collection holding the data keys. ex: "encryption.__keyVault"
+
+	SchemaMap: JSON schemas with encrypt rules by namespace, when empty the server side schemas are used
+
+	BypassAutoEncryption: only decrypt automatically, fields are encrypted with KeyVault.Encrypt
+
+	ExtraOptions: mongocryptd or crypt_shared settings. ex: {"cryptSharedLibPath": path}
+
+	EncryptedFieldsMap: Queryable Encryption fields by namespace, see EncryptedFields
+*/
+type AutoEncryption struct {
+	KMSProviders         map[string]map[string]interface{}
+	KeyVaultNamespace    string
+	SchemaMap            map[string]interface{}
+	BypassAutoEncryption bool
+	ExtraOptions         map[string]interface{}
+	EncryptedFieldsMap   map[string]interface{}
+}
+
+/*
+KeyVault object
+Manages data keys and explicitly encrypts and decrypts values
+*/
+type KeyVault struct {
+	ce *mongo.ClientEncryption
+}
+
+/*
+Turns on automatic field level encryption
+Only used when connecting
+
+	AutoEncryption encryption settings
+
+Returns:
+
+	an option - Option
+*/
+func WithAutoEncryption(ae AutoEncryption) Option {
+	return func(cf *config) {
+		cf.autoEncryption = &ae
+	}
+}
+
+/*
+Converts the settings into the driver representation
+*/
+func (ae *AutoEncryption) driver() *options.AutoEncryptionOptions {
+	opts := options.AutoEncryption().
+		SetKmsProviders(ae.KMSProviders).
+		SetKeyVaultNamespace(ae.KeyVaultNamespace).
+		SetBypassAutoEncryption(ae.BypassAutoEncryption)
+	if len(ae.SchemaMap) > 0 {
+		opts.SetSchemaMap(ae.SchemaMap)
+	}
+	if len(ae.ExtraOptions) > 0 {
+		opts.SetExtraOptions(ae.ExtraOptions)
+	}
+	if len(ae.EncryptedFieldsMap) > 0 {
+		opts.SetEncryptedFieldsMap(ae.EncryptedFieldsMap)
+	}
+	return opts
+}
+
+/*
+Opens the key vault configured with WithAutoEncryption
+
+Returns:
+
+	*KeyVault pointer to a key vault
+
+	an err - error
+*/
+func (c *Client) KeyVault() (*KeyVault, error) {
+	if c.connected() != nil {
+		return nil, notConnected("opening the key vault")
+	}
+	ae := c.cf.autoEncryption
+	if ae == nil {
+		return nil, errors.New("please configure encryption with WithAutoEncryption before opening the key vault")
+	}
+	ce, err := mongo.NewClientEncryption(c.cl(), options.ClientEncryption().
+		SetKeyVaultNamespace(ae.KeyVaultNamespace).
+		SetKmsProviders(ae.KMSProviders))
+	if err != nil {
+		return nil, err
+	}
+	return &KeyVault{ce: ce}, nil
+}
+
+/*
+Creates a data key in the key vault
+
+	string: KMS provider protecting the key. ex: "local", "aws"
+
+	interface{} master key of the provider, nil for "local". ex: bson.M{"region": region, "key": arn} for "aws"
+
+	...string: alternate names to refer to the key by
+
+Returns:
+
+	the id of the key - primitive.Binary
+
+	an err - error
+*/
+func (k *KeyVault) CreateDataKey(provider string, masterKey interface{}, altNames ...string) (primitive.Binary, error) {
+	opts := options.DataKey()
+	if masterKey != nil {
+		opts.SetMasterKey(masterKey)
+	}
+	if len(altNames) > 0 {
+		opts.SetKeyAltNames(altNames)
+	}
+	return k.ce.CreateDataKey(context.Background(), provider, opts)
+}
+
+/*
+Encrypts a value with the data key of an alternate name
+
+	interface{} value to encrypt
+
+	string: alternate name of the data key
+
+	string: AlgorithmDeterministic or AlgorithmRandom
+
+Returns:
+
+	the encrypted value - primitive.Binary
+
+	an err - error
+*/
+func (k *KeyVault) Encrypt(value interface{}, keyAltName string, algorithm string) (primitive.Binary, error) {
+	t, b, err := bson.MarshalValue(value)
+	if err != nil {
+		return primitive.Binary{}, err
+	}
+	return k.ce.Encrypt(context.Background(), bson.RawValue{Type: t, Value: b},
+		options.Encrypt().SetKeyAltName(keyAltName).SetAlgorithm(algorithm))
+}
+
+/*
+Decrypts a value encrypted with Encrypt
+
+	primitive.Binary encrypted value
+
+Returns:
+
+	the decrypted value - bson.RawValue
+
+	an err - error
+*/
+func (k *KeyVault) Decrypt(value primitive.Binary) (bson.RawValue, error) {
+	return k.ce.Decrypt(context.Background(), value)
+}
+
+/*
+Closes the key vault
+
+Returns:
+
+	an err - error
+*/
+func (k *KeyVault) Close() error {
+	return k.ce.Close(context.Background())
+}
@@ -0,0 +1,147 @@
+package driver
+
+import (
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+Errors returned by the client, check them with errors.Is
+The errors they were mapped from are kept, so errors.Is(err, mongo.ErrNoDocuments) still works
+*/
+var (
+	ErrNotFound         = errors.New("document not found")
+	ErrDuplicateKey     = errors.New("duplicate key")
+	ErrTimeout          = errors.New("operation timed out")
+	ErrNotConnected     = errors.New("client is not connected")
+	ErrNoDatabaseSet    = errors.New("no database set")
+	ErrNoCollectionSet  = errors.New("no collection set")
+	ErrShutdown         = errors.New("client is shutting down")
+	ErrReadOnly         = errors.New("client is read only")
+	ErrDocumentTooLarge = errors.New("document is too large")
+	ErrInvalidDocument  = errors.New("document is invalid")
+	ErrDryRun           = errors.New("write can't be simulated in dry-run mode")
+)
+
+/*
+DuplicateKeyError object
+Returned when a write violates a unique index, use errors.As to get the index
+
+	Index: name of the unique index, empty if the server didn't name it
+*/
+type DuplicateKeyError struct {
+	Index string
+	err   error
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return e.err.Error()
+}
+
+func (e *DuplicateKeyError) Is(target error) bool {
+	return target == ErrDuplicateKey
+}
+
+func (e *DuplicateKeyError) Unwrap() error {
+	return e.err
+}
+
+/*
+An error matching one of the errors of the client and wrapping the error it was mapped from
+*/
+type clientError struct {
+	kind error
+	err  error
+}
+
+func (e *clientError) Error() string {
+	return e.err.Error()
+}
+
+func (e *clientError) Is(target error) bool {
+	return target == e.kind
+}
+
+func (e *clientError) Unwrap() error {
+	return e.err
+}
+
+/*
+Maps an error of the mongo driver to the errors of the client
+*/
+func mapError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return &clientError{kind: ErrNotFound, err: err}
+	case mongo.IsDuplicateKeyError(err):
+		return &DuplicateKeyError{Index: duplicateIndex(err.Error()), err: err}
+	case mongo.IsTimeout(err):
+		return &clientError{kind: ErrTimeout, err: err}
+	case errors.Is(err, mongo.ErrClientDisconnected):
+		return &clientError{kind: ErrNotConnected, err: err}
+	}
+	return err
+}
+
+/*
+Name of the index in a duplicate key message. ex: "E11000 duplicate key error collection: db.users index: email_1 dup key: ..."
+*/
+func duplicateIndex(message string) string {
+	i := strings.Index(message, "index: ")
+	if i < 0 {
+		return ""
+	}
+	index := message[i+len("index: "):]
+	if j := strings.IndexByte(index, ' '); j >= 0 {
+		index = index[:j]
+	}
+	return index
+}
+
+/*
+Checks that the client can write
+*/
+func (c *Client) writable() error {
+	if c.cf.readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+/*
+Error returned when an operation needs a database
+*/
+func noDatabase(action string) error {
+	return &clientError{kind: ErrNoDatabaseSet, err: errors.New("please set a database before " + action)}
+}
+
+/*
+Error returned when an operation needs a collection
+*/
+func noCollection(action string) error {
+	return &clientError{kind: ErrNoCollectionSet, err: errors.New("please set a collection before " + action)}
+}
+
+/*
+Checks that a database and a collection are set before an operation on the collection
+*/
+func (c *Client) collection(action string) error {
+	if c.db() == nil {
+		return noDatabase(action)
+	}
+	if c.co() == nil {
+		return noCollection(action)
+	}
+	return nil
+}
+
+/*
+Error returned when an operation needs a connection
+*/
+func notConnected(action string) error {
+	return &clientError{kind: ErrNotConnected, err: errors.New("please connect before " + action)}
+}
@@ -0,0 +1,206 @@
+package driver
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+ExplainVerbosity is how much information explain returns
+*/
+type ExplainVerbosity string
+
+const (
+	ExplainQueryPlanner      ExplainVerbosity = "queryPlanner"
+	ExplainExecutionStats    ExplainVerbosity = "executionStats"
+	ExplainAllPlansExecution ExplainVerbosity = "allPlansExecution"
+)
+
+/*
+ExplainPlan object
+Summary of a query plan, the execution statistics are only set
+with the ExplainExecutionStats and ExplainAllPlansExecution verbosities
+
+	WinningPlan: stages of the chosen plan from the last to the first. ex: "LIMIT > FETCH > IXSCAN"
+
+	Indexes: indexes used by the plan, empty for a collection scan
+
+	CollectionScan: whether the plan scans the whole collection
+
+	DocsExamined: number of documents read
+
+	KeysExamined: number of index keys read
+
+	Returned: number of documents returned
+
+	ExecutionTimeMillis: time spent running the query
+
+	Raw: the complete explain output
+*/
+type ExplainPlan struct {
+	WinningPlan         string
+	Indexes             []string
+	CollectionScan      bool
+	DocsExamined        int64
+	KeysExamined        int64
+	Returned            int64
+	ExecutionTimeMillis int64
+	Raw                 bson.Raw
+}
+
+/*
+Explains how a find would run on the collection
+
+	interface{} filter to query objects by
+
+	*options.FindOptions options the find would run with
+
+	ExplainVerbosity how much to explain
+
+Returns:
+
+	the plan summary - *ExplainPlan
+
+	an err - error
+*/
+func (c *Client) ExplainFind(filter interface{}, opts *options.FindOptions, verbosity ExplainVerbosity) (*ExplainPlan, error) {
+	if err := c.collection("explaining a query"); err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	find := bson.D{{Key: "find", Value: c.co().Name()}, {Key: "filter", Value: filter}}
+	if opts != nil {
+		if opts.Sort != nil {
+			find = append(find, bson.E{Key: "sort", Value: opts.Sort})
+		}
+		if opts.Projection != nil {
+			find = append(find, bson.E{Key: "projection", Value: opts.Projection})
+		}
+		if opts.Skip != nil {
+			find = append(find, bson.E{Key: "skip", Value: *opts.Skip})
+		}
+		if opts.Limit != nil {
+			find = append(find, bson.E{Key: "limit", Value: *opts.Limit})
+		}
+		if opts.Hint != nil {
+			find = append(find, bson.E{Key: "hint", Value: opts.Hint})
+		}
+		if opts.Collation != nil {
+			find = append(find, bson.E{Key: "collation", Value: opts.Collation})
+		}
+	}
+	return c.explain(find, verbosity)
+}
+
+/*
+Explains how an aggregation would run on the collection
+
+	interface{} pipeline to explain
+
+	ExplainVerbosity how much to explain
+
+Returns:
+
+	the plan summary - *ExplainPlan
+
+	an err - error
+*/
+func (c *Client) ExplainAggregate(pipeline interface{}, verbosity ExplainVerbosity) (*ExplainPlan, error) {
+	if err := c.collection("explaining a query"); err != nil {
+		return nil, err
+	}
+	return c.explain(bson.D{
+		{Key: "aggregate", Value: c.co().Name()},
+		{Key: "pipeline", Value: pipeline},
+		{Key: "cursor", Value: bson.D{}},
+	}, verbosity)
+}
+
+func (c *Client) explain(cmd bson.D, verbosity ExplainVerbosity) (*ExplainPlan, error) {
+	if verbosity == "" {
+		verbosity = ExplainQueryPlanner
+	}
+	var raw bson.Raw
+	err := c.RunCommand("", bson.D{{Key: "explain", Value: cmd}, {Key: "verbosity", Value: string(verbosity)}}, &raw)
+	if err != nil {
+		return nil, err
+	}
+	return summarizePlan(raw), nil
+}
+
+/*
+Summarizes the output of explain
+*/
+func summarizePlan(raw bson.Raw) *ExplainPlan {
+	plan := &ExplainPlan{Raw: raw}
+	root := raw
+	// aggregations that use a query put its explain in their first stage
+	if stages, ok := raw.Lookup("stages").ArrayOK(); ok {
+		if first, ok := stages.Index(0).Value().DocumentOK(); ok {
+			if cursor, ok := first.Lookup("$cursor").DocumentOK(); ok {
+				root = cursor
+			}
+		}
+	}
+	winning, ok := root.Lookup("queryPlanner", "winningPlan").DocumentOK()
+	if ok {
+		// the slot based engine nests the plan one level deeper
+		if inner, ok := winning.Lookup("queryPlan").DocumentOK(); ok {
+			winning = inner
+		}
+		var stages []string
+		walkPlan(winning, func(stage bson.Raw) {
+			name := stage.Lookup("stage").StringValue()
+			stages = append(stages, name)
+			if name == "COLLSCAN" {
+				plan.CollectionScan = true
+			}
+			if index, ok := stage.Lookup("indexName").StringValueOK(); ok {
+				plan.Indexes = append(plan.Indexes, index)
+			}
+		})
+		plan.WinningPlan = strings.Join(stages, " > ")
+	}
+	if stats, ok := root.Lookup("executionStats").DocumentOK(); ok {
+		plan.DocsExamined = number(stats.Lookup("totalDocsExamined"))
+		plan.KeysExamined = number(stats.Lookup("totalKeysExamined"))
+		plan.Returned = number(stats.Lookup("nReturned"))
+		plan.ExecutionTimeMillis = number(stats.Lookup("executionTimeMillis"))
+	}
+	return plan
+}
+
+/*
+Calls fn on a stage of a plan then on the stages it reads from
+*/
+func walkPlan(stage bson.Raw, fn func(bson.Raw)) {
+	fn(stage)
+	if input, ok := stage.Lookup("inputStage").DocumentOK(); ok {
+		walkPlan(input, fn)
+	}
+	if inputs, ok := stage.Lookup("inputStages").ArrayOK(); ok {
+		values, _ := inputs.Values()
+		for _, v := range values {
+			if input, ok := v.DocumentOK(); ok {
+				walkPlan(input, fn)
+			}
+		}
+	}
+}
+
+/*
+Converts any numeric value to an int64, 0 if the value is not a number
+*/
+func number(v bson.RawValue) int64 {
+	if n, ok := v.AsInt64OK(); ok {
+		return n
+	}
+	if f, ok := v.DoubleOK(); ok {
+		return int64(f)
+	}
+	return 0
+}
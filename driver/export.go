@@ -0,0 +1,198 @@
+package driver
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+ExportFormat is the format documents are exported in
+*/
+type ExportFormat string
+
+const (
+	ExportJSON ExportFormat = "json" // one extended JSON document per line
+	ExportCSV  ExportFormat = "csv"  // one row per document with the columns of Fields
+)
+
+/*
+ExportField object
+A CSV column
+
+	Header: name of the column, Path when empty
+
+	Path: dotted path of the field. ex: address.city
+*/
+type ExportField struct {
+	Header string
+	Path   string
+}
+
+/*
+ExportOptions object
+Configures Export
+
+	Format: ExportJSON or ExportCSV
+
+	Filter: filter on the exported documents, nil for every document
+
+	Projection: projection of the exported documents. ex: Project().Exclude("password")
+
+	Sort: order of the exported documents
+
+	Fields: columns of the CSV export, required for ExportCSV
+
+	Canonical: export canonical instead of relaxed extended JSON
+
+	Scrubber: anonymizes the documents before they are written, can be nil
+*/
+type ExportOptions struct {
+	Format     ExportFormat
+	Filter     interface{}
+	Projection interface{}
+	Sort       interface{}
+	Fields     []ExportField
+	Canonical  bool
+	Scrubber   *Scrubber
+}
+
+/*
+Streams the documents of the collection that is set to a writer
+The export isn't bounded by the timeout of the client, fetching each batch is
+
+	io.Writer writer to export to
+
+	ExportOptions options to export with
+
+Returns:
+
+	the number of exported documents - int
+
+	an err - error
+*/
+func (c *Client) Export(w io.Writer, opts ExportOptions) (_ int, err error) {
+	defer c.observe("export", time.Now(), &err)
+	if err := c.collection("exporting it"); err != nil {
+		return 0, err
+	}
+	if opts.Format == ExportCSV && len(opts.Fields) == 0 {
+		return 0, errors.New("csv exports need Fields")
+	}
+	if opts.Filter == nil {
+		opts.Filter = bson.D{}
+	}
+	ctx, cancel := c.stream()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return 0, err
+	}
+	find := options.Find()
+	if opts.Projection != nil {
+		find.SetProjection(opts.Projection)
+	}
+	if opts.Sort != nil {
+		find.SetSort(opts.Sort)
+	}
+	var cursor *mongo.Cursor
+	err = c.first(ctx, func(ctx context.Context) (err error) {
+		cursor, err = c.co().Find(ctx, opts.Filter, c.cf.findOptions(), find)
+		return err
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	defer cursor.Close(ctx)
+
+	var cw *csv.Writer
+	if opts.Format == ExportCSV {
+		cw = csv.NewWriter(w)
+		header := make([]string, 0, len(opts.Fields))
+		for _, field := range opts.Fields {
+			if field.Header == "" {
+				field.Header = field.Path
+			}
+			header = append(header, field.Header)
+		}
+		if err := cw.Write(header); err != nil {
+			return 0, err
+		}
+	}
+
+	read := c.reader()
+	n := 0
+	for c.next(ctx, cursor) {
+		doc := cursor.Current
+		if read != nil {
+			if doc, err = read(doc); err != nil {
+				return n, err
+			}
+		}
+		if opts.Scrubber != nil {
+			if doc, err = opts.Scrubber.Scrub(doc); err != nil {
+				return n, err
+			}
+		}
+		if cw != nil {
+			row := make([]string, 0, len(opts.Fields))
+			for _, field := range opts.Fields {
+				row = append(row, csvValue(doc.Lookup(strings.Split(field.Path, ".")...)))
+			}
+			if err := cw.Write(row); err != nil {
+				return n, err
+			}
+		} else {
+			b, err := bson.MarshalExtJSON(doc, opts.Canonical, false)
+			if err != nil {
+				return n, err
+			}
+			if _, err := w.Write(append(b, '\n')); err != nil {
+				return n, err
+			}
+		}
+		n++
+	}
+	if cw != nil {
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return n, err
+		}
+	}
+	return n, mapError(cursor.Err())
+}
+
+/*
+Formats a value for a CSV cell, missing values are empty
+*/
+func csvValue(v bson.RawValue) string {
+	switch v.Type {
+	case 0, bsontype.Null, bsontype.Undefined:
+		return ""
+	case bsontype.String:
+		return v.StringValue()
+	case bsontype.Boolean:
+		return strconv.FormatBool(v.Boolean())
+	case bsontype.Int32, bsontype.Int64:
+		n, _ := v.AsInt64OK()
+		return strconv.FormatInt(n, 10)
+	case bsontype.Double:
+		return strconv.FormatFloat(v.Double(), 'f', -1, 64)
+	case bsontype.ObjectID:
+		return v.ObjectID().Hex()
+	case bsontype.DateTime:
+		return v.Time().UTC().Format(time.RFC3339Nano)
+	case bsontype.Decimal128:
+		return v.Decimal128().String()
+	}
+	return v.String()
+}
@@ -0,0 +1,250 @@
+package driver
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+Filter object
+Builds a query filter. ex: F("age").Gt(18).And(F("status").In("a", "b"))
+A filter is either a set of conditions on a single field or a
+composition of other filters
+*/
+type Filter struct {
+	field string
+	cond  bson.D
+	d     bson.D
+}
+
+/*
+Starts a filter on a field
+
+	string: field to filter by
+
+Returns:
+
+	*Filter pointer to a filter
+*/
+func F(field string) *Filter {
+	return &Filter{field: field, cond: bson.D{}}
+}
+
+/*
+Combines filters so that all of them must match
+
+	...*Filter: filters to combine
+
+Returns:
+
+	*Filter pointer to a filter
+*/
+func And(filters ...*Filter) *Filter {
+	return compose("$and", filters)
+}
+
+/*
+Combines filters so that at least one of them must match
+
+	...*Filter: filters to combine
+
+Returns:
+
+	*Filter pointer to a filter
+*/
+func Or(filters ...*Filter) *Filter {
+	return compose("$or", filters)
+}
+
+/*
+Combines filters so that none of them must match
+
+	...*Filter: filters to combine
+
+Returns:
+
+	*Filter pointer to a filter
+*/
+func Nor(filters ...*Filter) *Filter {
+	return compose("$nor", filters)
+}
+
+/*
+Negates a filter
+Field filters are negated with $not, other filters with $nor
+
+	*Filter: filter to negate
+
+Returns:
+
+	*Filter pointer to a filter
+*/
+func Not(filter *Filter) *Filter {
+	if filter.field == "" {
+		return Nor(filter)
+	}
+	return &Filter{d: bson.D{{Key: filter.field, Value: bson.D{{Key: "$not", Value: filter.cond}}}}}
+}
+
+func compose(op string, filters []*Filter) *Filter {
+	a := make(bson.A, 0, len(filters))
+	for _, filter := range filters {
+		a = append(a, filter.D())
+	}
+	return &Filter{d: bson.D{{Key: op, Value: a}}}
+}
+
+/*
+Combines the filter with others so that all of them must match
+*/
+func (f *Filter) And(filters ...*Filter) *Filter {
+	return And(append([]*Filter{f}, filters...)...)
+}
+
+/*
+Combines the filter with others so that at least one of them must match
+*/
+func (f *Filter) Or(filters ...*Filter) *Filter {
+	return Or(append([]*Filter{f}, filters...)...)
+}
+
+/*
+Combines the filter with others so that none of them must match
+*/
+func (f *Filter) Nor(filters ...*Filter) *Filter {
+	return Nor(append([]*Filter{f}, filters...)...)
+}
+
+/*
+Negates the filter
+*/
+func (f *Filter) Not() *Filter {
+	return Not(f)
+}
+
+/*
+Field is equal to the value
+*/
+func (f *Filter) Eq(value interface{}) *Filter {
+	return f.op("$eq", value)
+}
+
+/*
+Field is not equal to the value
+*/
+func (f *Filter) Ne(value interface{}) *Filter {
+	return f.op("$ne", value)
+}
+
+/*
+Field is greater than the value
+*/
+func (f *Filter) Gt(value interface{}) *Filter {
+	return f.op("$gt", value)
+}
+
+/*
+Field is greater than or equal to the value
+*/
+func (f *Filter) Gte(value interface{}) *Filter {
+	return f.op("$gte", value)
+}
+
+/*
+Field is less than the value
+*/
+func (f *Filter) Lt(value interface{}) *Filter {
+	return f.op("$lt", value)
+}
+
+/*
+Field is less than or equal to the value
+*/
+func (f *Filter) Lte(value interface{}) *Filter {
+	return f.op("$lte", value)
+}
+
+/*
+Field is equal to one of the values
+*/
+func (f *Filter) In(values ...interface{}) *Filter {
+	return f.op("$in", bson.A(values))
+}
+
+/*
+Field is equal to none of the values
+*/
+func (f *Filter) Nin(values ...interface{}) *Filter {
+	return f.op("$nin", bson.A(values))
+}
+
+/*
+Field exists or not
+*/
+func (f *Filter) Exists(exists bool) *Filter {
+	return f.op("$exists", exists)
+}
+
+/*
+Field matches the regular expression
+
+	string: pattern to match
+
+	string: regex options. ex: "i" for case insensitive
+*/
+func (f *Filter) Regex(pattern string, options string) *Filter {
+	f.op("$regex", pattern)
+	if options != "" {
+		f.op("$options", options)
+	}
+	return f
+}
+
+/*
+Array field has the given length
+*/
+func (f *Filter) Size(n int) *Filter {
+	return f.op("$size", n)
+}
+
+/*
+Array field contains all of the values
+*/
+func (f *Filter) All(values ...interface{}) *Filter {
+	return f.op("$all", bson.A(values))
+}
+
+/*
+Array field contains an element matching the filter
+*/
+func (f *Filter) ElemMatch(filter *Filter) *Filter {
+	return f.op("$elemMatch", filter.D())
+}
+
+func (f *Filter) op(op string, value interface{}) *Filter {
+	if f.field == "" { // conditions can only be added to field filters
+		return f
+	}
+	f.cond = append(f.cond, bson.E{Key: op, Value: value})
+	return f
+}
+
+/*
+Returns the filter document
+
+Returns:
+
+	a document - bson.D
+*/
+func (f *Filter) D() bson.D {
+	if f.field != "" {
+		return bson.D{{Key: f.field, Value: f.cond}}
+	}
+	return f.d
+}
+
+/*
+Lets the filter be passed directly wherever a filter document is expected
+*/
+func (f *Filter) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(f.D())
+}
@@ -0,0 +1,44 @@
+package driver
+
+import (
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter *Filter
+		want   string
+	}{
+		{"eq", F("name").Eq("ada"), `{"name":{"$eq":"ada"}}`},
+		{"range", F("age").Gte(18).Lt(65), `{"age":{"$gte":18,"$lt":65}}`},
+		{"in", F("status").In("a", "b"), `{"status":{"$in":["a","b"]}}`},
+		{"nin", F("status").Nin("c"), `{"status":{"$nin":["c"]}}`},
+		{"exists", F("email").Exists(false), `{"email":{"$exists":false}}`},
+		{"regex", F("name").Regex("^a", "i"), `{"name":{"$regex":"^a","$options":"i"}}`},
+		{"regex without options", F("name").Regex("^a", ""), `{"name":{"$regex":"^a"}}`},
+		{"size", F("tags").Size(2), `{"tags":{"$size":2}}`},
+		{"all", F("tags").All("a", "b"), `{"tags":{"$all":["a","b"]}}`},
+		{"elemMatch", F("grades").ElemMatch(F("score").Gt(90)), `{"grades":{"$elemMatch":{"score":{"$gt":90}}}}`},
+		{"and", And(F("a").Eq(1), F("b").Ne(2)), `{"$and":[{"a":{"$eq":1}},{"b":{"$ne":2}}]}`},
+		{"or method", F("a").Eq(1).Or(F("b").Eq(2)), `{"$or":[{"a":{"$eq":1}},{"b":{"$eq":2}}]}`},
+		{"nor", Nor(F("a").Eq(1)), `{"$nor":[{"a":{"$eq":1}}]}`},
+		{"not field", Not(F("a").Gt(1)), `{"a":{"$not":{"$gt":1}}}`},
+		{"not composition", And(F("a").Eq(1), F("b").Eq(2)).Not(), `{"$nor":[{"$and":[{"a":{"$eq":1}},{"b":{"$eq":2}}]}]}`},
+		{"conditions on a composition are ignored", And(F("a").Eq(1)).Eq(2), `{"$and":[{"a":{"$eq":1}}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extJSON(tt.filter.D()); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterMarshalBSON(t *testing.T) {
+	filter := F("age").Gt(18).And(F("status").In("a"))
+	if got, want := extJSON(filter), extJSON(filter.D()); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
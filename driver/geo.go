@@ -0,0 +1,194 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+Radius of the earth in meters, used to convert distances to radians
+*/
+const earthRadius = 6378100.0
+
+/*
+GeoPoint object
+A GeoJSON point, can be used as a field of stored documents
+*/
+type GeoPoint struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+/*
+GeoPolygon object
+A GeoJSON polygon made of an outer ring and optional holes
+*/
+type GeoPolygon struct {
+	Type        string        `bson:"type" json:"type"`
+	Coordinates [][][]float64 `bson:"coordinates" json:"coordinates"`
+}
+
+/*
+GeoNearQuery object
+Describes a $geoNear query
+
+	Field: 2dsphere indexed field, only needed when the collection has more than one
+
+	Near: point to sort the results by distance from
+
+	MinDistance: minimum distance in meters
+
+	MaxDistance: maximum distance in meters, 0 for no maximum
+
+	Filter: filter the results must match
+
+	DistanceField: field to put the distance in meters in, "distance" when empty
+
+	Limit: maximum number of results, 0 for no limit
+*/
+type GeoNearQuery struct {
+	Field         string
+	Near          GeoPoint
+	MinDistance   float64
+	MaxDistance   float64
+	Filter        interface{}
+	DistanceField string
+	Limit         int64
+}
+
+/*
+Creates a GeoJSON point
+Coordinates are in longitude, latitude order like GeoJSON
+*/
+func Point(longitude float64, latitude float64) GeoPoint {
+	return GeoPoint{Type: "Point", Coordinates: []float64{longitude, latitude}}
+}
+
+/*
+Creates a GeoJSON polygon from rings of [longitude, latitude] positions
+Rings that are not closed are closed automatically
+*/
+func Polygon(rings ...[][]float64) GeoPolygon {
+	polygon := GeoPolygon{Type: "Polygon", Coordinates: make([][][]float64, 0, len(rings))}
+	for _, ring := range rings {
+		if n := len(ring); n > 0 && (ring[0][0] != ring[n-1][0] || ring[0][1] != ring[n-1][1]) {
+			ring = append(ring, ring[0])
+		}
+		polygon.Coordinates = append(polygon.Coordinates, ring)
+	}
+	return polygon
+}
+
+/*
+Field is inside the geometry
+*/
+func (f *Filter) GeoWithin(geometry interface{}) *Filter {
+	return f.op("$geoWithin", bson.D{{Key: "$geometry", Value: geometry}})
+}
+
+/*
+Field is within a distance in meters of a point
+*/
+func (f *Filter) GeoWithinRadius(center GeoPoint, meters float64) *Filter {
+	return f.op("$geoWithin", bson.D{{Key: "$centerSphere", Value: bson.A{center.Coordinates, meters / earthRadius}}})
+}
+
+/*
+Field intersects the geometry
+*/
+func (f *Filter) GeoIntersects(geometry interface{}) *Filter {
+	return f.op("$geoIntersects", bson.D{{Key: "$geometry", Value: geometry}})
+}
+
+/*
+Adds a $geoNear stage, it must be the first stage of the pipeline
+*/
+func (p *PipelineBuilder) GeoNear(q GeoNearQuery) *PipelineBuilder {
+	distance := q.DistanceField
+	if distance == "" {
+		distance = "distance"
+	}
+	spec := bson.D{
+		{Key: "near", Value: q.Near},
+		{Key: "distanceField", Value: distance},
+		{Key: "spherical", Value: true},
+	}
+	if q.Field != "" {
+		spec = append(spec, bson.E{Key: "key", Value: q.Field})
+	}
+	if q.MinDistance > 0 {
+		spec = append(spec, bson.E{Key: "minDistance", Value: q.MinDistance})
+	}
+	if q.MaxDistance > 0 {
+		spec = append(spec, bson.E{Key: "maxDistance", Value: q.MaxDistance})
+	}
+	if q.Filter != nil {
+		spec = append(spec, bson.E{Key: "query", Value: q.Filter})
+	}
+	return p.Stage("$geoNear", spec)
+}
+
+/*
+Finds the objects closest to a point and decodes them into results
+Results are sorted from the closest to the furthest and hold their distance in meters
+
+	GeoNearQuery query to run
+
+	interface{} pointer to a slice to decode the results into
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) GeoNear(q GeoNearQuery, results interface{}) (err error) {
+	defer c.observe("geoNear", time.Now(), &err)
+	if err := c.collection("running a geo query"); err != nil {
+		return err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	p := Pipeline().GeoNear(q)
+	if q.Limit > 0 {
+		p.Limit(q.Limit)
+	}
+	// ping database
+	if err := c.ping(); err != nil {
+		return err
+	}
+	cursor, err := c.co().Aggregate(ctx, p.Stages(), c.cf.aggregateOptions())
+	if err != nil {
+		return mapError(err)
+	}
+	return c.all(ctx, cursor, results)
+}
+
+/*
+Creates a 2dsphere index on a GeoJSON field of the collection
+
+	string: field to index
+
+Returns:
+
+	the name of the index - string
+
+	an err - error
+*/
+func (c *Client) Create2dsphereIndex(field string) (_ string, err error) {
+	defer c.observe("create2dsphereIndex", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return "", err
+	}
+	if err := c.collection("creating an index"); err != nil {
+		return "", err
+	}
+	model := mongo.IndexModel{Keys: bson.D{{Key: field, Value: "2dsphere"}}}
+	if c.cf.dryRun != nil {
+		return "", c.dryRunChange("createIndex", model)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	name, err := c.co().Indexes().CreateOne(ctx, model)
+	return name, mapError(err)
+}
@@ -0,0 +1,148 @@
+package driver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+Creates a deterministic key from a list of parts
+The key can be used as the _id of a document created from an external
+event (ex: a webhook delivery id) so that handling the event twice
+never inserts two documents
+
+	...string: parts the key is made of
+
+Returns:
+
+	a key - string
+*/
+func IdempotencyKey(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+/*
+Makes sure the object has an _id before it is inserted
+If the object has no _id a new ObjectID is generated for it so that
+every attempt at inserting it uses the same _id. Objects with a string
+_id field get the hex of the ObjectID, so the stored _id is the one set on the field
+
+	interface{} object to insert
+
+Returns:
+
+	the document to insert - interface{}
+
+	the _id of the document, decoded into a Go value. ex: a primitive.ObjectID or a string - interface{}
+
+	whether the _id was generated - bool
+
+	an err - error
+*/
+func withID(reg *bsoncodec.Registry, object interface{}) (interface{}, interface{}, bool, error) {
+	b, err := bson.MarshalWithRegistry(reg, object)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if raw, err := bson.Raw(b).LookupErr("_id"); err == nil && !isZeroID(raw) {
+		var id interface{}
+		if err := raw.UnmarshalWithRegistry(reg, &id); err != nil {
+			return nil, nil, false, err
+		}
+		return object, id, false, nil
+	}
+	var id interface{} = primitive.NewObjectID()
+	if hasStringID(object) {
+		id = id.(primitive.ObjectID).Hex()
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(b, &doc); err != nil {
+		return nil, nil, false, err
+	}
+	pinned := bson.D{{Key: "_id", Value: id}}
+	for _, e := range doc {
+		if e.Key != "_id" {
+			pinned = append(pinned, e)
+		}
+	}
+	return pinned, id, true, nil
+}
+
+/*
+Checks if an _id is an empty ObjectID or an empty string
+Structs with an _id field without omitempty encode the zero value when no id was set
+*/
+func isZeroID(id bson.RawValue) bool {
+	if s, ok := id.StringValueOK(); ok {
+		return s == ""
+	}
+	oid, ok := id.ObjectIDOK()
+	return ok && oid.IsZero()
+}
+
+/*
+Checks if the _id field of a struct, or a pointer to one, is a string
+*/
+func hasStringID(object interface{}) bool {
+	field, ok := idField(reflect.ValueOf(object))
+	if !ok {
+		return false
+	}
+	t := field.Type()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.String
+}
+
+/*
+Field of a struct, or a pointer to one, tagged as the _id
+*/
+func idField(v reflect.Value) (reflect.Value, bool) {
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	for i := 0; i < v.NumField(); i++ {
+		if name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("bson"), ","); name == "_id" {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+/*
+Checks if a failed insert failed because the document was already inserted
+
+	context.Context context of the insert
+
+	error: error returned by the insert
+
+	interface{} _id of the inserted document
+
+Returns:
+
+	a boolean - bool
+*/
+func (c *Client) inserted(ctx context.Context, err error, id interface{}) bool {
+	if err == nil || !mongo.IsDuplicateKeyError(err) {
+		return false
+	}
+	n, err := c.co().CountDocuments(ctx, bson.D{{Key: "_id", Value: id}}, c.cf.countOptions())
+	return err == nil && n > 0
+}
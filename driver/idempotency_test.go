@@ -0,0 +1,159 @@
+package driver
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	if IdempotencyKey("a", "b") != IdempotencyKey("a", "b") {
+		t.Error("the key isn't deterministic")
+	}
+	// parts are separated so moving characters between them changes the key
+	if IdempotencyKey("ab", "c") == IdempotencyKey("a", "bc") {
+		t.Error("different parts got the same key")
+	}
+}
+
+func TestWithID(t *testing.T) {
+	type ObjectIDDoc struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Name string             `bson:"name"`
+	}
+	type StringIDDoc struct {
+		ID   string `bson:"_id"`
+		Name string `bson:"name"`
+	}
+	type PointerIDDoc struct {
+		ID   *string `bson:"_id,omitempty"`
+		Name string  `bson:"name"`
+	}
+	oid := primitive.NewObjectID()
+
+	tests := []struct {
+		name      string
+		object    interface{}
+		generated bool
+		stringID  bool
+		want      interface{}
+	}{
+		{"object id set", ObjectIDDoc{ID: oid}, false, false, oid},
+		{"zero object id", ObjectIDDoc{Name: "ada"}, true, false, nil},
+		{"string id set", StringIDDoc{ID: "ada"}, false, true, "ada"},
+		{"empty string id", StringIDDoc{Name: "ada"}, true, true, nil},
+		{"nil pointer id", &PointerIDDoc{Name: "ada"}, true, true, nil},
+		{"document without id", bson.D{{Key: "name", Value: "ada"}}, true, false, nil},
+		{"document with id", bson.D{{Key: "_id", Value: int32(7)}}, false, false, int32(7)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, id, generated, err := withID(bson.DefaultRegistry, tt.object)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if generated != tt.generated {
+				t.Fatalf("got generated %v, want %v", generated, tt.generated)
+			}
+			if !generated {
+				if id != tt.want {
+					t.Errorf("got id %v, want %v", id, tt.want)
+				}
+				return
+			}
+			if _, ok := id.(string); ok != tt.stringID {
+				t.Errorf("got id %T, want a string %v", id, tt.stringID)
+			}
+			// the document holds the generated id first
+			d, ok := doc.(bson.D)
+			if !ok || len(d) == 0 || d[0].Key != "_id" || d[0].Value != id {
+				t.Errorf("got document %v for id %v", doc, id)
+			}
+		})
+	}
+}
+
+func TestIsZeroID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   interface{}
+		want bool
+	}{
+		{"zero object id", primitive.NilObjectID, true},
+		{"object id", primitive.NewObjectID(), false},
+		{"empty string", "", true},
+		{"string", "ada", false},
+		{"zero number", int32(0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bson.Marshal(bson.D{{Key: "_id", Value: tt.id}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := isZeroID(bson.Raw(b).Lookup("_id")); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetID(t *testing.T) {
+	type ObjectIDDoc struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	type StringIDDoc struct {
+		ID string `bson:"_id,omitempty"`
+	}
+	type PointerIDDoc struct {
+		ID *primitive.ObjectID `bson:"_id"`
+	}
+	type InterfaceIDDoc struct {
+		ID interface{} `bson:"_id"`
+	}
+	oid := primitive.NewObjectID()
+
+	t.Run("object id", func(t *testing.T) {
+		doc := &ObjectIDDoc{}
+		setID(doc, oid)
+		if doc.ID != oid {
+			t.Errorf("got %v", doc.ID)
+		}
+	})
+	t.Run("string id", func(t *testing.T) {
+		doc := &StringIDDoc{}
+		setID(doc, oid.Hex())
+		if doc.ID != oid.Hex() {
+			t.Errorf("got %v", doc.ID)
+		}
+	})
+	t.Run("pointer id", func(t *testing.T) {
+		doc := &PointerIDDoc{}
+		setID(doc, oid)
+		if doc.ID == nil || *doc.ID != oid {
+			t.Errorf("got %v", doc.ID)
+		}
+	})
+	t.Run("interface id", func(t *testing.T) {
+		doc := &InterfaceIDDoc{}
+		setID(doc, oid)
+		if doc.ID != oid {
+			t.Errorf("got %v", doc.ID)
+		}
+	})
+	t.Run("mismatched type", func(t *testing.T) {
+		doc := &StringIDDoc{}
+		setID(doc, oid)
+		if doc.ID != "" {
+			t.Errorf("got %v", doc.ID)
+		}
+	})
+	t.Run("values aren't set", func(t *testing.T) {
+		doc := ObjectIDDoc{}
+		setID(doc, oid)
+		if !doc.ID.IsZero() {
+			t.Errorf("got %v", doc.ID)
+		}
+	})
+}
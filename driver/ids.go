@@ -0,0 +1,140 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Finds an object by its _id and decodes it into result
+
+	interface{} id of the object, a hex string or a primitive.ObjectID
+
+	interface{} pointer to decode the object into
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) FindByID(id interface{}, result interface{}) (err error) {
+	defer c.observe("findOne", time.Now(), &err)
+	if err := c.collection("finding an object"); err != nil {
+		return err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return err
+	}
+	return mapError(c.redactResult(c.findOne(ctx, bson.D{{Key: "_id", Value: objectID(id)}})).Decode(result))
+}
+
+/*
+Checks if an object matching the filter exists in the collection
+
+	interface{} filter to query object by
+
+Returns:
+
+	a boolean - bool
+
+	an err - error
+*/
+func (c *Client) Exists(filter interface{}) (_ bool, err error) {
+	defer c.observe("count", time.Now(), &err)
+	if err := c.collection("checking if an object exists"); err != nil {
+		return false, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return false, err
+	}
+	n, err := c.co().CountDocuments(ctx, filter, c.cf.countOptions(), options.Count().SetLimit(1))
+	if err != nil {
+		return false, mapError(err)
+	}
+	return n > 0, nil
+}
+
+/*
+Converts hex strings into ObjectIDs
+Any other value, including strings that are not ObjectIDs, is returned as is
+*/
+func objectID(id interface{}) interface{} {
+	if hex, ok := id.(string); ok {
+		if oid, err := primitive.ObjectIDFromHex(hex); err == nil {
+			return oid
+		}
+	}
+	return id
+}
+
+/*
+Generates a new ObjectID
+
+Returns:
+
+	the id - primitive.ObjectID
+*/
+func NewID() primitive.ObjectID {
+	return primitive.NewObjectID()
+}
+
+/*
+Parses the hex representation of an ObjectID
+
+	string: hex of the id. ex: "5f1d7e0a2b3c4d5e6f708192"
+
+Returns:
+
+	the id - primitive.ObjectID
+
+	an err - error, when the string is not an ObjectID
+*/
+func ParseID(hex string) (primitive.ObjectID, error) {
+	return primitive.ObjectIDFromHex(hex)
+}
+
+/*
+Checks if a string is the hex representation of an ObjectID, ex: to validate path parameters
+*/
+func IsValidID(hex string) bool {
+	return primitive.IsValidObjectID(hex)
+}
+
+/*
+Returns the time an ObjectID was generated at, to the second
+*/
+func IDTime(id primitive.ObjectID) time.Time {
+	return id.Timestamp()
+}
+
+/*
+Smallest ObjectID generated at a time, to compare ids with a time
+*/
+func IDFromTime(t time.Time) primitive.ObjectID {
+	return primitive.NewObjectIDFromTimestamp(t)
+}
+
+/*
+Matches the documents whose ObjectID _id was generated in a time window,
+the _id index makes it a cheap scan of a time bucket without a date field
+ex: c.FindMany(IDsBetween(from, from.Add(time.Hour)), nil)
+
+	time.Time start of the window, included
+
+	time.Time end of the window, excluded
+
+Returns:
+
+	*Filter pointer to a filter
+*/
+func IDsBetween(from time.Time, to time.Time) *Filter {
+	return F("_id").Gte(IDFromTime(from)).Lt(IDFromTime(to))
+}
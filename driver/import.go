@@ -0,0 +1,147 @@
+package driver
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+ImportOptions object
+Configures ImportNDJSON
+
+	BatchSize: number of documents per insert, 1000 when 0
+
+	StopOnError: stop at the first line that can't be parsed, validated or inserted instead of skipping it
+
+	OnProgress: called after every batch, can be nil
+*/
+type ImportOptions struct {
+	BatchSize   int
+	StopOnError bool
+	OnProgress  func(ImportProgress)
+}
+
+/*
+ImportProgress object
+Contains the progress of an import
+
+	Lines: number of non empty lines read
+
+	Inserted: number of documents inserted
+
+	Failed: number of lines that couldn't be parsed, validated or inserted
+*/
+type ImportProgress struct {
+	Lines    int
+	Inserted int
+	Failed   int
+}
+
+/*
+Imports newline delimited JSON into the collection that is set
+Each line is a document in JSON or MongoDB extended JSON, ex: the output of mongoexport
+
+	io.Reader reader to read the lines from
+
+	ImportOptions options to import with
+
+Returns:
+
+	the final progress - ImportProgress
+
+	an err - error
+*/
+func (c *Client) ImportNDJSON(r io.Reader, opts ImportOptions) (_ ImportProgress, err error) {
+	defer c.observe("importNDJSON", time.Now(), &err)
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return ImportProgress{}, err
+	}
+	var progress ImportProgress
+	if err := c.collection("importing"); err != nil {
+		return progress, err
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 1000
+	}
+	ctx, cancel := c.stream()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return progress, err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 3*maxBSONSize) // extended JSON is larger than the BSON it encodes
+	batch := make([]interface{}, 0, opts.BatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		ctx, cancel := c.step(ctx)
+		defer cancel()
+		var err error
+		if c.cf.dryRun != nil { // the batch counts as inserted
+			if _, err = c.dryRun(ctx, "importNDJSON", nil, batch, false); err == nil {
+				progress.Inserted += len(batch)
+			}
+		} else {
+			var res *mongo.InsertManyResult
+			res, err = c.co().InsertMany(ctx, batch, options.InsertMany().SetOrdered(opts.StopOnError))
+			if res != nil {
+				progress.Inserted += len(res.InsertedIDs)
+			}
+		}
+		var bulk mongo.BulkWriteException
+		if errors.As(err, &bulk) && !opts.StopOnError {
+			progress.Failed += len(bulk.WriteErrors)
+			err = nil
+		}
+		batch = batch[:0]
+		if opts.OnProgress != nil {
+			opts.OnProgress(progress)
+		}
+		return mapError(err)
+	}
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		progress.Lines++
+		var doc bson.D
+		err := bson.UnmarshalExtJSON(text, false, &doc)
+		var object interface{}
+		if err == nil {
+			object, err = c.prepare(doc)
+		}
+		if err != nil {
+			if opts.StopOnError {
+				return progress, fmt.Errorf("line %d: %w", line, err)
+			}
+			progress.Failed++
+			continue
+		}
+		batch = append(batch, object)
+		if len(batch) == opts.BatchSize {
+			if err := flush(); err != nil {
+				return progress, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return progress, err
+	}
+	return progress, flush()
+}
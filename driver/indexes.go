@@ -0,0 +1,74 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Creates an index on the collection
+
+	interface{} keys of the index. ex: bson.D{{Key: "email", Value: 1}}
+
+	*options.IndexOptions options to create the index with. ex: options.Index().SetUnique(true)
+
+Returns:
+
+	the name of the index - string
+
+	an err - error
+*/
+func (c *Client) CreateIndex(keys interface{}, opts *options.IndexOptions) (_ string, err error) {
+	defer c.observe("createIndex", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return "", err
+	}
+	if err := c.collection("creating an index"); err != nil {
+		return "", err
+	}
+	// the index keeps the collation it was created with so the client default is copied in
+	merged := c.cf.indexOptions()
+	if opts != nil {
+		collation := merged.Collation
+		*merged = *opts
+		if merged.Collation == nil {
+			merged.Collation = collation
+		}
+	}
+	model := mongo.IndexModel{Keys: keys, Options: merged}
+	if c.cf.dryRun != nil {
+		return "", c.dryRunChange("createIndex", model)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	name, err := c.co().Indexes().CreateOne(ctx, model)
+	return name, mapError(err)
+}
+
+/*
+Lists the indexes of the collection
+
+Returns:
+
+	the index specifications - []bson.M
+
+	an err - error
+*/
+func (c *Client) ListIndexes() (_ []bson.M, err error) {
+	defer c.observe("listIndexes", time.Now(), &err)
+	if err := c.collection("listing indexes"); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	cursor, err := c.co().Indexes().List(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	var indexes []bson.M
+	err = cursor.All(ctx, &indexes)
+	return indexes, mapError(err)
+}
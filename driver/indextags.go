@@ -0,0 +1,344 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+IndexReport object
+Result of EnsureIndexes
+
+	Created: names of the declared indexes that were missing and got created
+
+	Drifted: declared indexes existing with other options, they are left as they are
+
+	Extra: names of the indexes of the collection that the model doesn't declare
+*/
+type IndexReport struct {
+	Created []string
+	Drifted []IndexDrift
+	Extra   []string
+}
+
+/*
+IndexDrift object
+Difference between a declared index and the existing one
+
+	Name: name of the existing index
+
+	Reason: what differs. ex: "unique is false, declared true"
+*/
+type IndexDrift struct {
+	Name   string
+	Reason string
+}
+
+/*
+Index declared by the tags of a model
+*/
+type declaredIndex struct {
+	group  string
+	keys   bson.D
+	unique bool
+	sparse bool
+	ttl    *int32
+}
+
+/*
+Returns the indexes declared by the index tags of a struct
+A tag holds comma separated directives. ex: `bson:"email" index:"unique"`
+
+	asc, desc, text, 2dsphere, hashed: type of the key, asc when none is given
+
+	unique, sparse: options of the index
+
+	ttl:<duration>: expire the documents after a duration, ex: ttl:24h
+
+	group:<name>: fields of the same group make one compound index named after the group,
+	in the order of the fields. The options of any field apply to the whole index
+
+	interface{} struct or pointer to a struct
+
+Returns:
+
+	the indexes - []mongo.IndexModel
+
+	an err - error
+*/
+func IndexesOf(model interface{}) ([]mongo.IndexModel, error) {
+	declared, err := declaredIndexes(model)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]mongo.IndexModel, len(declared))
+	for i, index := range declared {
+		models[i] = index.model()
+	}
+	return models, nil
+}
+
+/*
+Creates the indexes declared by the tags of a model that the collection doesn't have
+Call it at startup. Indexes existing with other options are reported, not changed,
+since rebuilding an index can be long and is better done on purpose. See IndexesOf
+
+	interface{} struct or pointer to a struct declaring the indexes
+
+Returns:
+
+	the report - *IndexReport
+
+	an err - error, ErrDryRun in dry-run mode when indexes are missing
+*/
+func (c *Client) EnsureIndexes(model interface{}) (_ *IndexReport, err error) {
+	defer c.observe("ensureIndexes", time.Now(), &err)
+	if err := c.collection("ensuring indexes"); err != nil {
+		return nil, err
+	}
+	declared, err := declaredIndexes(model)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	cursor, err := c.co().Indexes().List(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	var existing []struct {
+		Name               string `bson:"name"`
+		Key                bson.D `bson:"key"`
+		Unique             bool   `bson:"unique"`
+		Sparse             bool   `bson:"sparse"`
+		ExpireAfterSeconds *int32 `bson:"expireAfterSeconds"`
+	}
+	if err := cursor.All(ctx, &existing); err != nil {
+		return nil, mapError(err)
+	}
+
+	report := &IndexReport{}
+	matched := map[string]bool{"_id_": true}
+	reported := false
+	for _, index := range declared {
+		found := false
+		for _, e := range existing {
+			if !sameKeys(index.keys, e.Key) {
+				continue
+			}
+			found, matched[e.Name] = true, true
+			var reasons []string
+			if e.Unique != index.unique {
+				reasons = append(reasons, fmt.Sprintf("unique is %t, declared %t", e.Unique, index.unique))
+			}
+			if e.Sparse != index.sparse {
+				reasons = append(reasons, fmt.Sprintf("sparse is %t, declared %t", e.Sparse, index.sparse))
+			}
+			if ttl, want := seconds(e.ExpireAfterSeconds), seconds(index.ttl); ttl != want {
+				reasons = append(reasons, fmt.Sprintf("ttl is %s, declared %s", ttl, want))
+			}
+			if len(reasons) > 0 {
+				report.Drifted = append(report.Drifted, IndexDrift{Name: e.Name, Reason: strings.Join(reasons, ", ")})
+			}
+			break
+		}
+		if found {
+			continue
+		}
+		name, err := c.CreateIndex(index.keys, index.model().Options)
+		if errors.Is(err, ErrDryRun) { // report the other missing indexes too
+			reported = true
+			continue
+		}
+		if err != nil {
+			return report, mapError(err)
+		}
+		report.Created = append(report.Created, name)
+	}
+	for _, e := range existing {
+		if !matched[e.Name] {
+			report.Extra = append(report.Extra, e.Name)
+		}
+	}
+	if reported {
+		return report, ErrDryRun
+	}
+	return report, nil
+}
+
+func (i declaredIndex) model() mongo.IndexModel {
+	opts := options.Index()
+	if i.group != "" {
+		opts.SetName(i.group)
+	}
+	if i.unique {
+		opts.SetUnique(true)
+	}
+	if i.sparse {
+		opts.SetSparse(true)
+	}
+	if i.ttl != nil {
+		opts.SetExpireAfterSeconds(*i.ttl)
+	}
+	return mongo.IndexModel{Keys: i.keys, Options: opts}
+}
+
+/*
+Collects the indexes declared by the fields of a model
+*/
+func declaredIndexes(model interface{}) ([]*declaredIndex, error) {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("indexes must be declared on a struct")
+	}
+	var indexes []*declaredIndex
+	groups := map[string]*declaredIndex{}
+	if err := collectIndexes(t, "", map[reflect.Type]bool{}, groups, &indexes); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+func collectIndexes(t reflect.Type, prefix string, seen map[reflect.Type]bool, groups map[string]*declaredIndex, indexes *[]*declaredIndex) error {
+	if seen[t] { // recursive types
+		return nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, flags, _ := strings.Cut(field.Tag.Get("bson"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name) // default name of the bson codec
+		}
+		path := prefix + name
+		ft := field.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != tTime {
+			sub := path + "."
+			if strings.Contains(flags, "inline") {
+				sub = prefix
+			}
+			if err := collectIndexes(ft, sub, seen, groups, indexes); err != nil {
+				return err
+			}
+		}
+		tag, ok := field.Tag.Lookup("index")
+		if !ok {
+			continue
+		}
+		if err := declareIndex(path, tag, groups, indexes); err != nil {
+			return fmt.Errorf("index tag of %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+/*
+Adds the index declared by the tag of a field
+*/
+func declareIndex(path string, tag string, groups map[string]*declaredIndex, indexes *[]*declaredIndex) error {
+	index := &declaredIndex{}
+	var value interface{} = 1
+	for _, directive := range strings.Split(tag, ",") {
+		key, arg, _ := strings.Cut(strings.TrimSpace(directive), ":")
+		switch key {
+		case "", "asc", "1":
+		case "desc", "-1":
+			value = -1
+		case "text", "2dsphere", "hashed":
+			value = key
+		case "unique":
+			index.unique = true
+		case "sparse":
+			index.sparse = true
+		case "ttl":
+			d, err := time.ParseDuration(arg)
+			if err != nil {
+				return err
+			}
+			ttl := int32(d / time.Second)
+			index.ttl = &ttl
+		case "group":
+			if arg == "" {
+				return errors.New("group needs a name")
+			}
+			index.group = arg
+		default:
+			return errors.New("unknown directive " + key)
+		}
+	}
+	key := bson.E{Key: path, Value: value}
+	if index.group == "" {
+		index.keys = bson.D{key}
+		*indexes = append(*indexes, index)
+		return nil
+	}
+	group, ok := groups[index.group]
+	if !ok {
+		group = &declaredIndex{group: index.group}
+		groups[index.group] = group
+		*indexes = append(*indexes, group)
+	}
+	group.keys = append(group.keys, key)
+	group.unique = group.unique || index.unique
+	group.sparse = group.sparse || index.sparse
+	if index.ttl != nil {
+		group.ttl = index.ttl
+	}
+	return nil
+}
+
+/*
+Checks if two index key documents are the same, 1 and 1.0 are equal
+*/
+func sameKeys(a bson.D, b bson.D) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Key != b[i].Key || fmt.Sprint(keyValue(a[i].Value)) != fmt.Sprint(keyValue(b[i].Value)) {
+			return false
+		}
+	}
+	return true
+}
+
+func keyValue(v interface{}) interface{} {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return v
+}
+
+func seconds(ttl *int32) string {
+	if ttl == nil {
+		return "none"
+	}
+	return (time.Duration(*ttl) * time.Second).String()
+}
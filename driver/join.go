@@ -0,0 +1,123 @@
+package driver
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+Join object
+Describes how to join documents from another collection with $lookup
+Either the LocalField/ForeignField pair or Pipeline must be set
+
+	From: collection to join
+
+	LocalField: field of the documents in the collection
+
+	ForeignField: field of the documents in the joined collection
+
+	Let: variables available in Pipeline. ex: bson.D{{Key: "user", Value: "$_id"}}
+
+	Pipeline: pipeline to run on the joined collection
+
+	As: field to put the joined documents in
+*/
+type Join struct {
+	From         string
+	LocalField   string
+	ForeignField string
+	Let          bson.D
+	Pipeline     *PipelineBuilder
+	As           string
+}
+
+/*
+Finds objects by a filter and joins a single document from another collection into each
+The As field holds the first joined document and is missing when none matched,
+so it can be decoded into a struct or pointer field
+
+	interface{} filter to query objects by
+
+	Join how to join the other collection
+
+	interface{} pointer to a slice to decode the objects into
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) JoinOne(filter interface{}, join Join, results interface{}) error {
+	if err := c.collection("joining objects"); err != nil {
+		return err
+	}
+	p, err := join.pipeline(filter)
+	if err != nil {
+		return err
+	}
+	p.AddFields(bson.D{{Key: join.As, Value: bson.D{{Key: "$arrayElemAt", Value: bson.A{"$" + join.As, 0}}}}})
+	return c.join(p, results)
+}
+
+/*
+Finds objects by a filter and joins the matching documents from another collection into each
+The As field holds an array of the joined documents and can be decoded into a slice field
+
+	interface{} filter to query objects by
+
+	Join how to join the other collection
+
+	interface{} pointer to a slice to decode the objects into
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) JoinMany(filter interface{}, join Join, results interface{}) error {
+	if err := c.collection("joining objects"); err != nil {
+		return err
+	}
+	p, err := join.pipeline(filter)
+	if err != nil {
+		return err
+	}
+	return c.join(p, results)
+}
+
+func (c *Client) join(p *PipelineBuilder, results interface{}) (err error) {
+	defer c.observe("join", time.Now(), &err)
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return err
+	}
+	cursor, err := c.co().Aggregate(ctx, p.Stages(), c.cf.aggregateOptions())
+	if err != nil {
+		return mapError(err)
+	}
+	return c.all(ctx, cursor, results)
+}
+
+/*
+Builds the pipeline matching the filter and running the $lookup
+*/
+func (j Join) pipeline(filter interface{}) (*PipelineBuilder, error) {
+	if j.From == "" || j.As == "" {
+		return nil, errors.New("join needs a From collection and an As field")
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	p := Pipeline().Match(filter)
+	switch {
+	case j.Pipeline != nil:
+		p.LookupPipeline(j.From, j.Let, j.Pipeline, j.As)
+	case j.LocalField != "" && j.ForeignField != "":
+		p.Lookup(j.From, j.LocalField, j.ForeignField, j.As)
+	default:
+		return nil, errors.New("join needs either a Pipeline or a LocalField and ForeignField")
+	}
+	return p, nil
+}
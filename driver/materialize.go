@@ -0,0 +1,226 @@
+package driver
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+Actions of $merge for the results matching a document of the target collection
+*/
+const (
+	MergeReplace      = "replace"
+	MergeKeepExisting = "keepExisting"
+	MergeMerge        = "merge"
+	MergeFail         = "fail"
+)
+
+/*
+Actions of $merge for the results not matching any document of the target collection
+*/
+const (
+	MergeInsert  = "insert"
+	MergeDiscard = "discard"
+)
+
+/*
+MergeOptions object
+Describes how $merge writes the results of a pipeline into a collection
+
+	Into: target collection
+
+	Database: database of the target collection, empty for the database of the client
+
+	On: fields identifying a result in the target collection, _id when empty. They need a unique index
+
+	WhenMatched: MergeReplace, MergeKeepExisting, MergeMerge or MergeFail, MergeMerge when empty
+
+	WhenNotMatched: MergeInsert, MergeDiscard or MergeFail, MergeInsert when empty
+*/
+type MergeOptions struct {
+	Into           string
+	Database       string
+	On             []string
+	WhenMatched    string
+	WhenNotMatched string
+}
+
+/*
+Writes the results of the pipeline into a collection, replacing it
+It must be the last stage of the pipeline
+*/
+func (p *PipelineBuilder) Out(collection string) *PipelineBuilder {
+	return p.Stage("$out", collection)
+}
+
+/*
+Merges the results of the pipeline into a collection
+It must be the last stage of the pipeline
+*/
+func (p *PipelineBuilder) Merge(opts MergeOptions) *PipelineBuilder {
+	into := interface{}(opts.Into)
+	if opts.Database != "" {
+		into = bson.D{{Key: "db", Value: opts.Database}, {Key: "coll", Value: opts.Into}}
+	}
+	spec := bson.D{{Key: "into", Value: into}}
+	if len(opts.On) == 1 {
+		spec = append(spec, bson.E{Key: "on", Value: opts.On[0]})
+	} else if len(opts.On) > 1 {
+		spec = append(spec, bson.E{Key: "on", Value: opts.On})
+	}
+	if opts.WhenMatched != "" {
+		spec = append(spec, bson.E{Key: "whenMatched", Value: opts.WhenMatched})
+	}
+	if opts.WhenNotMatched != "" {
+		spec = append(spec, bson.E{Key: "whenNotMatched", Value: opts.WhenNotMatched})
+	}
+	return p.Stage("$merge", spec)
+}
+
+/*
+Runs a pipeline on the collection and replaces a collection with its results
+The target is replaced atomically once the pipeline is done, ex: to rebuild a rollup on a schedule
+
+	*PipelineBuilder pipeline computing the documents, without the $out stage
+
+	string: collection to replace
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) MaterializeTo(pipeline *PipelineBuilder, target string) error {
+	if err := c.collection("materializing a pipeline"); err != nil {
+		return err
+	}
+	if target == "" {
+		return errors.New("materializing needs a target collection")
+	}
+	// copy the stages so the pipeline of the caller is left untouched
+	stages := append(mongo.Pipeline{}, pipeline.Stages()...)
+	if err := c.materialize(append(stages, bson.D{{Key: "$out", Value: target}})); err != nil {
+		return err
+	}
+	c.invalidateNamespace(c.co().Database().Name() + "." + target)
+	return nil
+}
+
+/*
+Runs a pipeline on the collection and merges its results into a collection
+Unlike MaterializeTo the target keeps the documents the pipeline doesn't return
+
+	*PipelineBuilder pipeline computing the documents, without the $merge stage
+
+	MergeOptions target collection and how to merge the results
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) MergeTo(pipeline *PipelineBuilder, opts MergeOptions) error {
+	if err := c.collection("materializing a pipeline"); err != nil {
+		return err
+	}
+	if opts.Into == "" {
+		return errors.New("merging needs a target collection")
+	}
+	stages := append(mongo.Pipeline{}, pipeline.Stages()...)
+	stages = append(stages, Pipeline().Merge(opts).Stages()...)
+	if err := c.materialize(stages); err != nil {
+		return err
+	}
+	db := opts.Database
+	if db == "" {
+		db = c.co().Database().Name()
+	}
+	c.invalidateNamespace(db + "." + opts.Into)
+	return nil
+}
+
+/*
+Runs a pipeline ending with a write stage
+*/
+func (c *Client) materialize(stages interface{}) (err error) {
+	defer c.observe("aggregate", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return err
+	}
+	if err := c.collection("materializing a pipeline"); err != nil {
+		return err
+	}
+	if c.cf.dryRun != nil {
+		_, err := c.dryRun(ctx, "aggregate", nil, stages, false)
+		return err
+	}
+	cursor, err := c.co().Aggregate(ctx, stages, c.cf.aggregateOptions())
+	if err != nil {
+		return mapError(err)
+	}
+	return cursor.Close(ctx)
+}
+
+/*
+Namespace written by the last stage of a pipeline, $out or $merge
+
+	interface{} pipeline, ex: mongo.Pipeline or bson.A
+
+Returns:
+
+	the namespace, empty when the pipeline doesn't write - string
+
+	an err - error
+*/
+func (c *Client) writeTarget(pipeline interface{}) (string, error) {
+	b, err := bson.MarshalWithRegistry(c.cf.codecRegistry(), bson.D{{Key: "pipeline", Value: pipeline}})
+	if err != nil {
+		return "", err
+	}
+	stages, ok := bson.Raw(b).Lookup("pipeline").ArrayOK()
+	if !ok {
+		return "", nil
+	}
+	values, err := stages.Values()
+	if err != nil || len(values) == 0 {
+		return "", err
+	}
+	stage, ok := values[len(values)-1].DocumentOK()
+	if !ok {
+		return "", nil
+	}
+	elements, err := stage.Elements()
+	if err != nil || len(elements) == 0 {
+		return "", err
+	}
+	target := elements[0].Value()
+	switch elements[0].Key() {
+	case "$out":
+	case "$merge":
+		if spec, ok := target.DocumentOK(); ok {
+			target = spec.Lookup("into")
+		}
+	default:
+		return "", nil
+	}
+	db := c.co().Database().Name()
+	if coll, ok := target.StringValueOK(); ok {
+		return db + "." + coll, nil
+	}
+	spec, _ := target.DocumentOK()
+	if name, ok := spec.Lookup("db").StringValueOK(); ok {
+		db = name
+	}
+	coll, ok := spec.Lookup("coll").StringValueOK()
+	if !ok {
+		return "", errors.New("pipeline writes to a collection without a name")
+	}
+	return db + "." + coll, nil
+}
@@ -0,0 +1,904 @@
+package driver
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.mongodb.org/mongo-driver/tag"
+)
+
+/*
+Option configures a Client
+Options can be passed to NewClient to set client wide defaults
+or to Client.With to override them for a set of operations
+*/
+type Option func(*config)
+
+/*
+config holds every setting that can be changed through an Option
+*/
+type config struct {
+	writeConcern *writeconcern.WriteConcern
+	readConcern  *readconcern.ReadConcern
+	readMode     readpref.Mode
+	readHedge    *bool
+	readTags     []tag.Set
+	maxStaleness time.Duration
+	retryWrites  *bool
+	retryReads   *bool
+	compressors  []string
+	zlibLevel    *int
+	zstdLevel    *int
+	serverAPI    *options.ServerAPIOptions
+	collation    *options.Collation
+	arrayFilters []interface{}
+
+	autoEncryption  *AutoEncryption
+	redactions      []RedactRule
+	permissions     []string
+	cache           *readCache
+	timeout         *time.Duration
+	allowDiskUse    *bool
+	batchSize       *int32
+	noCursorTimeout *bool
+	hint            interface{}
+	comment         *string
+	monitor         time.Duration
+	readOnly        bool
+	dryRun          func(DryRunWrite)
+	pingTimeout     time.Duration
+	reconnect       *ReconnectPolicy
+	retries         *int
+	budget          *retryBudget
+	size            *SizeGuard
+	validator       func(interface{}) error
+	sequences       string
+	credentials     CredentialsProvider
+	pool            *Pool
+	socket          string
+	direct          bool
+	codecs          []func(*bsoncodec.RegistryBuilder)
+	registry        *bsoncodec.Registry
+	flight          *flightGroup
+	counts          *countCache
+	built           *bsoncodec.Registry
+
+	callTimeout        bool
+	scopedTimeout      *time.Duration
+	collectionTimeouts map[string]time.Duration
+}
+
+/*
+WriteConcern describes how many members must acknowledge a write
+
+	W: number of members that have to acknowledge the write
+
+	Majority: wait for a majority of members, takes precedence over W
+
+	Tag: name of a tag set to acknowledge the write, takes precedence over W
+
+	Journal: wait for the write to be committed to the journal
+
+	WTimeout: how long to wait for the acknowledgement
+*/
+type WriteConcern struct {
+	W        int
+	Majority bool
+	Tag      string
+	Journal  bool
+	WTimeout time.Duration
+}
+
+/*
+ReadConcernLevel is the isolation level used for reads
+*/
+type ReadConcernLevel string
+
+/*
+ReadPreference is the members of the replica set reads are sent to
+*/
+type ReadPreference string
+
+const (
+	ReadPrimary            ReadPreference = "primary"
+	ReadPrimaryPreferred   ReadPreference = "primaryPreferred"
+	ReadSecondary          ReadPreference = "secondary"
+	ReadSecondaryPreferred ReadPreference = "secondaryPreferred"
+	ReadNearest            ReadPreference = "nearest"
+)
+
+/*
+Compressor is an algorithm used to compress network traffic
+*/
+type Compressor string
+
+const (
+	CompressorZstd   Compressor = "zstd"
+	CompressorSnappy Compressor = "snappy"
+	CompressorZlib   Compressor = "zlib"
+)
+
+/*
+Collation describes the language rules used to compare strings
+
+	Locale: ICU locale. ex: "en", "fr_CA"
+
+	Strength: level of comparison, CollationPrimary ignores case and diacritics
+
+	CaseLevel: compare case at the primary and secondary strengths
+
+	NumericOrdering: compare numeric strings as numbers. ex: "10" > "9"
+*/
+type Collation struct {
+	Locale          string
+	Strength        int
+	CaseLevel       bool
+	NumericOrdering bool
+}
+
+const (
+	CollationPrimary    = 1 // base letters only
+	CollationSecondary  = 2 // base letters and diacritics
+	CollationTertiary   = 3 // base letters, diacritics and case
+	CollationQuaternary = 4 // also punctuation
+	CollationIdentical  = 5 // every difference
+)
+
+const (
+	ReadConcernLocal        ReadConcernLevel = "local"
+	ReadConcernMajority     ReadConcernLevel = "majority"
+	ReadConcernSnapshot     ReadConcernLevel = "snapshot"
+	ReadConcernLinearizable ReadConcernLevel = "linearizable"
+)
+
+/*
+Sets the write concern used for write operations
+
+	WriteConcern: acknowledgement settings for writes
+
+Returns:
+
+	an option - Option
+*/
+func WithWriteConcern(wc WriteConcern) Option {
+	return func(cf *config) {
+		cf.writeConcern = wc.driver()
+	}
+}
+
+/*
+Sets the read concern used for read operations
+
+	ReadConcernLevel: level of isolation for reads
+
+Returns:
+
+	an option - Option
+*/
+func WithReadConcern(level ReadConcernLevel) Option {
+	return func(cf *config) {
+		cf.readConcern = readconcern.New(readconcern.Level(string(level)))
+	}
+}
+
+/*
+Sets the members of the replica set reads are sent to
+Reads from secondaries may return stale data. Panics on a mode that isn't one
+of the ReadPreference constants, so a typo can't silently read from the primary
+
+	ReadPreference: members to read from
+
+Returns:
+
+	an option - Option
+*/
+func WithReadPreference(preference ReadPreference) Option {
+	mode, err := readpref.ModeFromString(string(preference))
+	if err != nil {
+		panic(fmt.Sprintf("driver: invalid read preference %q", string(preference)))
+	}
+	return func(cf *config) {
+		cf.readMode = mode
+	}
+}
+
+/*
+Sends reads to two members of each shard and uses the first answer, lowering
+the latency of reads through mongos. Needs a read preference other than ReadPrimary
+Use it per operation with With. ex: c.With(WithReadPreference(ReadNearest), WithHedgedReads(true))
+
+	bool: whether to hedge reads
+
+Returns:
+
+	an option - Option
+*/
+func WithHedgedReads(enabled bool) Option {
+	return func(cf *config) {
+		cf.readHedge = &enabled
+	}
+}
+
+/*
+Only reads from the members matching one of the tag sets, tried in order
+Needs a read preference other than ReadPrimary. ex: to pin reporting queries
+to analytics nodes c.With(WithReadPreference(ReadSecondary), WithReadTags(map[string]string{"nodeType": "ANALYTICS"}))
+
+	...map[string]string: tag sets, a member matches a set when it has all of its tags
+
+Returns:
+
+	an option - Option
+*/
+func WithReadTags(sets ...map[string]string) Option {
+	return func(cf *config) {
+		cf.readTags = nil
+		for _, set := range sets {
+			cf.readTags = append(cf.readTags, tag.NewTagSetFromMap(set))
+		}
+	}
+}
+
+/*
+Stops reading from secondaries lagging behind the primary by more than a duration
+Needs a read preference other than ReadPrimary, the server requires at least 90 seconds
+
+	time.Duration maximum replication lag
+
+Returns:
+
+	an option - Option
+*/
+func WithMaxStaleness(d time.Duration) Option {
+	return func(cf *config) {
+		cf.maxStaleness = d
+	}
+}
+
+/*
+Turns the driver retryable writes on or off
+They are on by default
+
+	bool: whether to retry writes
+
+Returns:
+
+	an option - Option
+*/
+func WithRetryWrites(retry bool) Option {
+	return func(cf *config) {
+		cf.retryWrites = &retry
+	}
+}
+
+/*
+Turns the driver retryable reads on or off
+They are on by default
+
+	bool: whether to retry reads
+
+Returns:
+
+	an option - Option
+*/
+func WithRetryReads(retry bool) Option {
+	return func(cf *config) {
+		cf.retryReads = &retry
+	}
+}
+
+/*
+Enables network compression
+The compressors are offered to the server in order of preference
+and the first one it supports is used. Only used when connecting
+
+	...Compressor: compressors to offer to the server
+
+Returns:
+
+	an option - Option
+*/
+func WithCompressors(compressors ...Compressor) Option {
+	return func(cf *config) {
+		cf.compressors = make([]string, 0, len(compressors))
+		for _, compressor := range compressors {
+			cf.compressors = append(cf.compressors, string(compressor))
+		}
+	}
+}
+
+/*
+Sets the level used by the zlib compressor
+From -1 (default) to 9 (best compression). Only used when connecting
+
+	int: compression level
+
+Returns:
+
+	an option - Option
+*/
+func WithZlibLevel(level int) Option {
+	return func(cf *config) {
+		cf.zlibLevel = &level
+	}
+}
+
+/*
+Sets the level used by the zstd compressor
+From 1 (fastest) to 20 (best compression). Only used when connecting
+
+	int: compression level
+
+Returns:
+
+	an option - Option
+*/
+func WithZstdLevel(level int) Option {
+	return func(cf *config) {
+		cf.zstdLevel = &level
+	}
+}
+
+/*
+Sets the Stable API version the client declares to the server
+Only used when connecting
+
+	string: api version. ex: "1"
+
+	bool: fail commands that are not part of the api version
+
+	bool: fail commands that are deprecated in the api version
+
+Returns:
+
+	an option - Option
+*/
+func WithServerAPI(version string, strict bool, deprecationErrors bool) Option {
+	return func(cf *config) {
+		cf.serverAPI = options.ServerAPI(options.ServerAPIVersion(version)).
+			SetStrict(strict).
+			SetDeprecationErrors(deprecationErrors)
+	}
+}
+
+/*
+Sets the collation used to compare strings in queries, updates, deletes and indexes
+Options passed to an operation take precedence
+
+	Collation: language rules to use
+
+Returns:
+
+	an option - Option
+*/
+func WithCollation(collation Collation) Option {
+	return func(cf *config) {
+		cf.collation = collation.driver()
+	}
+}
+
+/*
+Sets the filters selecting the array elements updated through $[identifier]
+Meant to be used for a single update. ex: c.With(WithArrayFilters(F("g.score").Gte(85))).UpdateMany(filter, update, nil)
+
+	...interface{}: filters on the identifiers used in the update
+
+Returns:
+
+	an option - Option
+*/
+func WithArrayFilters(filters ...interface{}) Option {
+	return func(cf *config) {
+		cf.arrayFilters = filters
+	}
+}
+
+/*
+Sets how long an operation can run before it is stopped
+The deadline is set on the context of the operation and sent to the server as
+maxTimeMS, so reads that run over it are killed on the server as well.
+Set it on NewClient for a default and override it for a single call. ex: c.With(WithTimeout(time.Minute)).FindMany(filter, nil)
+Operations are stopped after DefaultTimeout when no timeout is set, see WithCollectionTimeout
+
+	time.Duration time an operation can run for, 0 for no deadline
+
+Returns:
+
+	an option - Option
+*/
+func WithTimeout(timeout time.Duration) Option {
+	return func(cf *config) {
+		cf.timeout = &timeout
+	}
+}
+
+/*
+Sets how long a ping can wait for the server before it fails
+Without it a ping waits for the server selection timeout of the driver, 30
+seconds by default, which makes health checks hang on degraded clusters.
+Operations ping the server first, so it bounds how long they wait for it too
+
+	time.Duration time a ping can wait for
+
+Returns:
+
+	an option - Option
+*/
+func WithPingTimeout(timeout time.Duration) Option {
+	return func(cf *config) {
+		cf.pingTimeout = timeout
+	}
+}
+
+/*
+Lets the sorts and groups of finds and aggregations write temporary files
+when they need more than the 100 megabytes of memory they are allowed
+
+	bool: allow writing temporary files
+
+Returns:
+
+	an option - Option
+*/
+func WithAllowDiskUse(allow bool) Option {
+	return func(cf *config) {
+		cf.allowDiskUse = &allow
+	}
+}
+
+/*
+Sets the number of documents returned by each batch of the cursors of finds and aggregations
+
+	int32: documents per batch
+
+Returns:
+
+	an option - Option
+*/
+func WithBatchSize(size int32) Option {
+	return func(cf *config) {
+		cf.batchSize = &size
+	}
+}
+
+/*
+Keeps the server from closing the cursors of finds after 10 minutes of inactivity
+Close those cursors once done with them, the server keeps them open otherwise
+
+	bool: disable the cursor timeout
+
+Returns:
+
+	an option - Option
+*/
+func WithNoCursorTimeout(disable bool) Option {
+	return func(cf *config) {
+		cf.noCursorTimeout = &disable
+	}
+}
+
+/*
+Forces the index used by finds, aggregations, counts, updates, replacements and deletes
+Meant to be used for a single operation. ex: c.With(WithHint("email_1")).FindOne(filter)
+
+	interface{} name of the index, or its keys. ex: bson.D{{Key: "email", Value: 1}}
+
+Returns:
+
+	an option - Option
+*/
+func WithHint(hint interface{}) Option {
+	return func(cf *config) {
+		cf.hint = hint
+	}
+}
+
+/*
+Attaches a comment to the operations, it shows in the profiler, the logs and currentOp
+ex: c.With(WithComment("checkout: load cart")).FindOne(filter)
+
+	string: comment to attach
+
+Returns:
+
+	an option - Option
+*/
+func WithComment(comment string) Option {
+	return func(cf *config) {
+		cf.comment = &comment
+	}
+}
+
+/*
+Makes the client read only, its write methods return ErrReadOnly without contacting the server
+RunCommand is not checked as the client can't tell which commands write
+
+Returns:
+
+	an option - Option
+*/
+func WithReadOnly() Option {
+	return func(cf *config) {
+		cf.readOnly = true
+	}
+}
+
+/*
+Fetches the credentials from a provider when connecting instead of using the ones given to NewClient
+ex: NewClient("", "", url, WithCredentialsProvider(&secrets.Vault{Address: addr, Token: token, Path: "mongo"}))
+
+	CredentialsProvider provider of the username and password
+
+Returns:
+
+	an option - Option
+*/
+func WithCredentialsProvider(provider CredentialsProvider) Option {
+	return func(cf *config) {
+		cf.credentials = provider
+	}
+}
+
+/*
+Converts the collation into the driver representation
+*/
+func (co Collation) driver() *options.Collation {
+	return &options.Collation{
+		Locale:          co.Locale,
+		Strength:        co.Strength,
+		CaseLevel:       co.CaseLevel,
+		NumericOrdering: co.NumericOrdering,
+	}
+}
+
+/*
+Converts the write concern into the driver representation
+*/
+func (wc WriteConcern) driver() *writeconcern.WriteConcern {
+	var opts []writeconcern.Option
+	switch {
+	case wc.Majority:
+		opts = append(opts, writeconcern.WMajority())
+	case wc.Tag != "":
+		opts = append(opts, writeconcern.WTagSet(wc.Tag))
+	case wc.W > 0:
+		opts = append(opts, writeconcern.W(wc.W))
+	}
+	if wc.Journal {
+		opts = append(opts, writeconcern.J(true))
+	}
+	if wc.WTimeout > 0 {
+		opts = append(opts, writeconcern.WTimeout(wc.WTimeout))
+	}
+	return writeconcern.New(opts...)
+}
+
+/*
+Builds the config from a list of options
+*/
+func newConfig(opts []Option) config {
+	var cf config
+	cf.apply(opts)
+	return cf
+}
+
+/*
+Applies options to the config, building the codec registry again when they change it
+*/
+func (cf *config) apply(opts []Option) {
+	codecs := len(cf.codecs)
+	for _, opt := range opts {
+		opt(cf)
+	}
+	if len(cf.codecs) != codecs {
+		rb := bson.NewRegistryBuilder()
+		for _, register := range cf.codecs {
+			register(rb)
+		}
+		cf.built = rb.Build()
+	}
+}
+
+/*
+Applies the config to the options used to connect the client
+*/
+func (cf *config) clientOptions(opts *options.ClientOptions) *options.ClientOptions {
+	if cf.writeConcern != nil {
+		opts.SetWriteConcern(cf.writeConcern)
+	}
+	if cf.readConcern != nil {
+		opts.SetReadConcern(cf.readConcern)
+	}
+	if rp := cf.readPreference(); rp != nil {
+		opts.SetReadPreference(rp)
+	}
+	if cf.retryWrites != nil {
+		opts.SetRetryWrites(*cf.retryWrites)
+	}
+	if cf.retryReads != nil {
+		opts.SetRetryReads(*cf.retryReads)
+	}
+	if len(cf.compressors) > 0 {
+		opts.SetCompressors(cf.compressors)
+	}
+	if cf.zlibLevel != nil {
+		opts.SetZlibLevel(*cf.zlibLevel)
+	}
+	if cf.zstdLevel != nil {
+		opts.SetZstdLevel(*cf.zstdLevel)
+	}
+	if cf.serverAPI != nil {
+		opts.SetServerAPIOptions(cf.serverAPI)
+	}
+	if cf.autoEncryption != nil {
+		opts.SetAutoEncryptionOptions(cf.autoEncryption.driver())
+	}
+	// the timeout isn't set on the driver, it would bound the calls that lift it. See operation
+	if cf.pool != nil {
+		cf.pool.apply(opts)
+	}
+	if cf.registry != nil || cf.built != nil {
+		opts.SetRegistry(cf.codecRegistry())
+	}
+	return opts
+}
+
+/*
+Codec registry used to encode and decode documents
+*/
+func (cf *config) codecRegistry() *bsoncodec.Registry {
+	if cf.registry != nil {
+		return cf.registry
+	}
+	if cf.built != nil {
+		return cf.built
+	}
+	return bson.DefaultRegistry
+}
+
+/*
+Read preference built from the read options, nil for the driver default
+*/
+func (cf *config) readPreference() *readpref.ReadPref {
+	if cf.readMode == 0 {
+		return nil
+	}
+	var opts []readpref.Option
+	// primary reads always go to the primary, the other settings don't apply
+	if cf.readMode != readpref.PrimaryMode {
+		if cf.readHedge != nil {
+			opts = append(opts, readpref.WithHedgeEnabled(*cf.readHedge))
+		}
+		if len(cf.readTags) > 0 {
+			opts = append(opts, readpref.WithTagSets(cf.readTags...))
+		}
+		if cf.maxStaleness > 0 {
+			opts = append(opts, readpref.WithMaxStaleness(cf.maxStaleness))
+		}
+	}
+	rp, err := readpref.New(cf.readMode, opts...)
+	if err != nil {
+		return nil
+	}
+	return rp
+}
+
+/*
+Options used when getting a database handle
+*/
+func (cf *config) databaseOptions() *options.DatabaseOptions {
+	opts := options.Database()
+	if cf.writeConcern != nil {
+		opts.SetWriteConcern(cf.writeConcern)
+	}
+	if cf.readConcern != nil {
+		opts.SetReadConcern(cf.readConcern)
+	}
+	if rp := cf.readPreference(); rp != nil {
+		opts.SetReadPreference(rp)
+	}
+	if cf.registry != nil || cf.built != nil {
+		opts.SetRegistry(cf.codecRegistry())
+	}
+	return opts
+}
+
+/*
+Options used when getting a collection handle
+*/
+func (cf *config) collectionOptions() *options.CollectionOptions {
+	opts := options.Collection()
+	if cf.writeConcern != nil {
+		opts.SetWriteConcern(cf.writeConcern)
+	}
+	if cf.readConcern != nil {
+		opts.SetReadConcern(cf.readConcern)
+	}
+	if rp := cf.readPreference(); rp != nil {
+		opts.SetReadPreference(rp)
+	}
+	if cf.registry != nil || cf.built != nil {
+		opts.SetRegistry(cf.codecRegistry())
+	}
+	return opts
+}
+
+/*
+Default options of each operation
+They are passed before the options of the operation so that those take precedence
+*/
+func (cf *config) findOptions() *options.FindOptions {
+	opts := options.Find()
+	if cf.collation != nil {
+		opts.SetCollation(cf.collation)
+	}
+	if timeout, ok := cf.deadline(); ok && timeout > 0 {
+		opts.SetMaxTime(timeout)
+	}
+	if cf.allowDiskUse != nil {
+		opts.SetAllowDiskUse(*cf.allowDiskUse)
+	}
+	if cf.batchSize != nil {
+		opts.SetBatchSize(*cf.batchSize)
+	}
+	if cf.noCursorTimeout != nil {
+		opts.SetNoCursorTimeout(*cf.noCursorTimeout)
+	}
+	if cf.hint != nil {
+		opts.SetHint(cf.hint)
+	}
+	if cf.comment != nil {
+		opts.SetComment(*cf.comment)
+	}
+	return opts
+}
+
+func (cf *config) findOneOptions() *options.FindOneOptions {
+	opts := options.FindOne()
+	if cf.collation != nil {
+		opts.SetCollation(cf.collation)
+	}
+	if timeout, ok := cf.deadline(); ok && timeout > 0 {
+		opts.SetMaxTime(timeout)
+	}
+	if cf.hint != nil {
+		opts.SetHint(cf.hint)
+	}
+	if cf.comment != nil {
+		opts.SetComment(*cf.comment)
+	}
+	return opts
+}
+
+func (cf *config) aggregateOptions() *options.AggregateOptions {
+	opts := options.Aggregate()
+	if cf.collation != nil {
+		opts.SetCollation(cf.collation)
+	}
+	if timeout, ok := cf.deadline(); ok && timeout > 0 {
+		opts.SetMaxTime(timeout)
+	}
+	if cf.allowDiskUse != nil {
+		opts.SetAllowDiskUse(*cf.allowDiskUse)
+	}
+	if cf.batchSize != nil {
+		opts.SetBatchSize(*cf.batchSize)
+	}
+	if cf.hint != nil {
+		opts.SetHint(cf.hint)
+	}
+	if cf.comment != nil {
+		opts.SetComment(*cf.comment)
+	}
+	return opts
+}
+
+func (cf *config) countOptions() *options.CountOptions {
+	opts := options.Count()
+	if cf.collation != nil {
+		opts.SetCollation(cf.collation)
+	}
+	if timeout, ok := cf.deadline(); ok && timeout > 0 {
+		opts.SetMaxTime(timeout)
+	}
+	if cf.hint != nil {
+		opts.SetHint(cf.hint)
+	}
+	if cf.comment != nil {
+		opts.SetComment(*cf.comment)
+	}
+	return opts
+}
+
+func (cf *config) distinctOptions() *options.DistinctOptions {
+	opts := options.Distinct()
+	if cf.collation != nil {
+		opts.SetCollation(cf.collation)
+	}
+	if timeout, ok := cf.deadline(); ok && timeout > 0 {
+		opts.SetMaxTime(timeout)
+	}
+	if cf.comment != nil {
+		opts.SetComment(*cf.comment)
+	}
+	return opts
+}
+
+func (cf *config) updateOptions() *options.UpdateOptions {
+	opts := options.Update()
+	if cf.collation != nil {
+		opts.SetCollation(cf.collation)
+	}
+	if len(cf.arrayFilters) > 0 {
+		opts.SetArrayFilters(options.ArrayFilters{Filters: cf.arrayFilters})
+	}
+	if cf.hint != nil {
+		opts.SetHint(cf.hint)
+	}
+	if cf.comment != nil {
+		opts.SetComment(*cf.comment)
+	}
+	return opts
+}
+
+func (cf *config) findOneAndUpdateOptions() *options.FindOneAndUpdateOptions {
+	opts := options.FindOneAndUpdate()
+	if cf.collation != nil {
+		opts.SetCollation(cf.collation)
+	}
+	if len(cf.arrayFilters) > 0 {
+		opts.SetArrayFilters(options.ArrayFilters{Filters: cf.arrayFilters})
+	}
+	if timeout, ok := cf.deadline(); ok && timeout > 0 {
+		opts.SetMaxTime(timeout)
+	}
+	if cf.hint != nil {
+		opts.SetHint(cf.hint)
+	}
+	if cf.comment != nil {
+		opts.SetComment(*cf.comment)
+	}
+	return opts
+}
+
+func (cf *config) replaceOptions() *options.ReplaceOptions {
+	opts := options.Replace()
+	if cf.collation != nil {
+		opts.SetCollation(cf.collation)
+	}
+	if cf.hint != nil {
+		opts.SetHint(cf.hint)
+	}
+	if cf.comment != nil {
+		opts.SetComment(*cf.comment)
+	}
+	return opts
+}
+
+func (cf *config) deleteOptions() *options.DeleteOptions {
+	opts := options.Delete()
+	if cf.collation != nil {
+		opts.SetCollation(cf.collation)
+	}
+	if cf.hint != nil {
+		opts.SetHint(cf.hint)
+	}
+	if cf.comment != nil {
+		opts.SetComment(*cf.comment)
+	}
+	return opts
+}
+
+func (cf *config) indexOptions() *options.IndexOptions {
+	opts := options.Index()
+	if cf.collation != nil {
+		opts.SetCollation(cf.collation)
+	}
+	return opts
+}
@@ -0,0 +1,37 @@
+package driver
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+func TestWithReadPreference(t *testing.T) {
+	tests := []struct {
+		preference ReadPreference
+		want       readpref.Mode
+	}{
+		{ReadPrimary, readpref.PrimaryMode},
+		{ReadPrimaryPreferred, readpref.PrimaryPreferredMode},
+		{ReadSecondary, readpref.SecondaryMode},
+		{ReadSecondaryPreferred, readpref.SecondaryPreferredMode},
+		{ReadNearest, readpref.NearestMode},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.preference), func(t *testing.T) {
+			cf := newConfig([]Option{WithReadPreference(tt.preference)})
+			if got := cf.readPreference().Mode(); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithReadPreferenceUnknown(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("an unknown read preference was accepted")
+		}
+	}()
+	WithReadPreference("secondaryPrefered")
+}
@@ -0,0 +1,223 @@
+package driver
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Largest document the server accepts, in bytes
+*/
+const MaxDocumentSize = 16 * 1024 * 1024
+
+/*
+Key of the document replacing a field stored in GridFS
+*/
+const overflowKey = "__gridfs"
+
+/*
+SizePolicy is what a SizeGuard does with documents over its limit
+*/
+type SizePolicy int
+
+const (
+	SizeReject SizePolicy = iota
+	SizeOverflow
+)
+
+/*
+SizeGuard object
+Checks the size of documents before they are written
+
+	Policy: SizeReject fails the write with ErrDocumentTooLarge, SizeOverflow moves the
+	largest fields to GridFS until the document fits and puts them back when it is read
+
+	Limit: largest document in bytes, MaxDocumentSize when 0
+
+	Bucket: GridFS bucket of the overflowing fields, "fs" when empty
+*/
+type SizeGuard struct {
+	Policy SizePolicy
+	Limit  int
+	Bucket string
+}
+
+/*
+Checks the size of the documents inserted or replaced before they reach the server
+The documents of InsertOne, InsertMany, ReplaceOne, Save and Upsert are checked.
+With SizeOverflow the fields moved to GridFS are named after the collection, the
+_id and the field, and are not deleted with the document they belong to
+
+	SizeGuard how to check the documents
+
+Returns:
+
+	an option - Option
+*/
+func WithSizeGuard(guard SizeGuard) Option {
+	return func(cf *config) {
+		if guard.Limit <= 0 || guard.Limit > MaxDocumentSize {
+			guard.Limit = MaxDocumentSize
+		}
+		if guard.Bucket == "" {
+			guard.Bucket = options.DefaultName
+		}
+		cf.size = &guard
+	}
+}
+
+/*
+Makes a document fit the limit of the size guard, if any
+*/
+func (c *Client) fit(document interface{}) (interface{}, error) {
+	guard := c.cf.size
+	if guard == nil {
+		return document, nil
+	}
+	b, err := bson.MarshalWithRegistry(c.cf.codecRegistry(), document)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) <= guard.Limit {
+		return document, nil
+	}
+	if guard.Policy != SizeOverflow {
+		return nil, tooLarge(len(b), guard.Limit)
+	}
+
+	elements, err := bson.Raw(b).Elements()
+	if err != nil {
+		return nil, err
+	}
+	// move the largest fields first so as few fields as possible are moved
+	order := make([]int, 0, len(elements))
+	for i, e := range elements {
+		if e.Key() != "_id" {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(elements[order[i]]) > len(elements[order[j]])
+	})
+	bucket, err := c.bucket()
+	if err != nil {
+		return nil, err
+	}
+	id := bson.Raw(b).Lookup("_id")
+	size := len(b)
+	moved := map[int]primitive.ObjectID{}
+	for _, i := range order {
+		if size <= guard.Limit {
+			break
+		}
+		e := elements[i]
+		value, err := bson.Marshal(bson.D{{Key: "v", Value: e.Value()}})
+		if err != nil {
+			return nil, err
+		}
+		file := primitive.NewObjectID()
+		name := fmt.Sprintf("%s/%s/%s", c.namespace(), id, e.Key())
+		metadata := bson.D{{Key: "collection", Value: c.namespace()}, {Key: "field", Value: e.Key()}}
+		if id.Type != 0 {
+			metadata = append(metadata, bson.E{Key: "id", Value: id})
+		}
+		if err := bucket.UploadFromStreamWithID(file, name, bytes.NewReader(value), options.GridFSUpload().SetMetadata(metadata)); err != nil {
+			return nil, err
+		}
+		moved[i] = file
+		// the field keeps its key and holds a small reference document instead
+		size -= len(e) - len(e.Key()) - 2 - len(bsonReference(file))
+	}
+	if size > guard.Limit {
+		return nil, tooLarge(size, guard.Limit)
+	}
+	d := make(bson.D, 0, len(elements))
+	for i, e := range elements {
+		if file, ok := moved[i]; ok {
+			d = append(d, bson.E{Key: e.Key(), Value: bson.D{{Key: overflowKey, Value: file}}})
+			continue
+		}
+		d = append(d, bson.E{Key: e.Key(), Value: e.Value()})
+	}
+	return d, nil
+}
+
+/*
+Puts back the fields of a document that were moved to GridFS
+*/
+func (c *Client) rehydrate(doc bson.Raw) (bson.Raw, error) {
+	elements, err := doc.Elements()
+	if err != nil {
+		return nil, err
+	}
+	var d bson.D
+	for i, e := range elements {
+		file, ok := overflowReference(e.Value())
+		if !ok {
+			if d != nil {
+				d = append(d, bson.E{Key: e.Key(), Value: e.Value()})
+			}
+			continue
+		}
+		if d == nil {
+			d = make(bson.D, 0, len(elements))
+			for _, previous := range elements[:i] {
+				d = append(d, bson.E{Key: previous.Key(), Value: previous.Value()})
+			}
+		}
+		bucket, err := c.bucket()
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if _, err := bucket.DownloadToStream(file, &buf); err != nil {
+			return nil, err
+		}
+		value, err := bson.Raw(buf.Bytes()).LookupErr("v")
+		if err != nil {
+			return nil, err
+		}
+		d = append(d, bson.E{Key: e.Key(), Value: value})
+	}
+	if d == nil { // nothing was moved
+		return doc, nil
+	}
+	return bson.Marshal(d)
+}
+
+/*
+File of a field moved to GridFS
+*/
+func overflowReference(v bson.RawValue) (primitive.ObjectID, bool) {
+	ref, ok := v.DocumentOK()
+	if !ok {
+		return primitive.ObjectID{}, false
+	}
+	elements, err := ref.Elements()
+	if err != nil || len(elements) != 1 || elements[0].Key() != overflowKey {
+		return primitive.ObjectID{}, false
+	}
+	return elements[0].Value().ObjectIDOK()
+}
+
+func bsonReference(file primitive.ObjectID) []byte {
+	b, _ := bson.Marshal(bson.D{{Key: overflowKey, Value: file}})
+	return b
+}
+
+func (c *Client) bucket() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(c.db(), options.GridFSBucket().SetName(c.cf.size.Bucket))
+}
+
+/*
+Error returned when a document is over the limit of the size guard
+*/
+func tooLarge(size int, limit int) error {
+	return &clientError{kind: ErrDocumentTooLarge, err: fmt.Errorf("document is %d bytes, over the limit of %d bytes", size, limit)}
+}
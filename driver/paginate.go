@@ -0,0 +1,341 @@
+package driver
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Page object
+Contains the metadata of a page returned by Paginate
+*/
+type Page struct {
+	Page    int64 `json:"page"`
+	PerPage int64 `json:"perPage"`
+	Total   int64 `json:"total"`
+	Pages   int64 `json:"pages"`
+	HasNext bool  `json:"hasNext"`
+}
+
+/*
+Keyset object
+Describes how PaginateAfter walks through a collection
+
+	Field: field to page by, ties are broken by _id. _id when empty
+
+	Descending: walk from the largest to the smallest value
+
+	PerPage: number of objects per page
+*/
+type Keyset struct {
+	Field      string
+	Descending bool
+	PerPage    int64
+}
+
+/*
+Position of the last object of a page, encoded in page tokens
+*/
+type keysetToken struct {
+	Value bson.RawValue `bson:"v"`
+	ID    bson.RawValue `bson:"id"`
+}
+
+/*
+Finds a page of objects by a filter and decodes them into results
+Pages start at 1. Uses skip so prefer PaginateAfter for deep pages of large collections
+
+	interface{} filter to query objects by
+
+	int64 page to return
+
+	int64 number of objects per page
+
+	interface{} pointer to a slice to decode the objects into
+
+	*options.FindOptions options to query collection with, ex: a sort
+
+Returns:
+
+	the page metadata - *Page
+
+	an err - error
+*/
+func (c *Client) Paginate(filter interface{}, page int64, perPage int64, results interface{}, opts *options.FindOptions) (_ *Page, err error) {
+	defer c.observe("paginate", time.Now(), &err)
+	if err := c.collection("paginating objects"); err != nil {
+		return nil, err
+	}
+	if page < 1 || perPage < 1 {
+		return nil, errors.New("page and perPage must be greater than 0")
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return nil, err
+	}
+
+	total, err := c.co().CountDocuments(ctx, filter, c.cf.countOptions())
+	if err != nil {
+		return nil, mapError(err)
+	}
+	cursor, err := c.co().Find(ctx, filter, c.cf.findOptions(), opts, options.Find().SetSkip((page-1)*perPage).SetLimit(perPage))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if err := c.all(ctx, cursor, results); err != nil {
+		return nil, mapError(err)
+	}
+	return &Page{
+		Page:    page,
+		PerPage: perPage,
+		Total:   total,
+		Pages:   (total + perPage - 1) / perPage,
+		HasNext: page*perPage < total,
+	}, nil
+}
+
+/*
+Finds a page of objects by a filter and counts every matching object in a single $facet aggregation
+Saves the round trip of the separate count of Paginate, the page has to fit in a 16MB document
+
+	interface{} filter to query objects by
+
+	int64 page to return
+
+	int64 number of objects per page
+
+	interface{} sort of the objects, nil to keep the natural order. ex: Sort().Desc("created")
+
+	interface{} pointer to a slice to decode the objects into
+
+Returns:
+
+	the page metadata - *Page
+
+	an err - error
+*/
+func (c *Client) PaginateWithTotal(filter interface{}, page int64, perPage int64, sort interface{}, results interface{}) (_ *Page, err error) {
+	defer c.observe("paginate", time.Now(), &err)
+	if err := c.collection("paginating objects"); err != nil {
+		return nil, err
+	}
+	if page < 1 || perPage < 1 {
+		return nil, errors.New("page and perPage must be greater than 0")
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return nil, err
+	}
+
+	items := Pipeline()
+	if sort != nil {
+		items.Sort(sort)
+	}
+	items.Skip((page - 1) * perPage).Limit(perPage)
+	p := Pipeline().Match(filter).Facet(map[string]*PipelineBuilder{
+		"items": items,
+		"total": Pipeline().Count("n"),
+	})
+	cursor, err := c.co().Aggregate(ctx, p.Stages(), c.cf.aggregateOptions())
+	if err != nil {
+		return nil, mapError(err)
+	}
+	var facets []struct {
+		Items []bson.Raw `bson:"items"`
+		Total []struct {
+			N int64 `bson:"n"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, mapError(err)
+	}
+	var raws []bson.Raw
+	var total int64
+	if len(facets) > 0 {
+		raws = facets[0].Items
+		// $count outputs nothing when no object matches
+		if len(facets[0].Total) > 0 {
+			total = facets[0].Total[0].N
+		}
+	}
+	if raws, err = c.redactAll(raws); err != nil {
+		return nil, err
+	}
+	if err := decodeAll(c.cf.codecRegistry(), raws, results); err != nil {
+		return nil, err
+	}
+	return &Page{
+		Page:    page,
+		PerPage: perPage,
+		Total:   total,
+		Pages:   (total + perPage - 1) / perPage,
+		HasNext: page*perPage < total,
+	}, nil
+}
+
+/*
+Finds the page of objects following a page token and decodes them into results
+Pages are found with a range query on the keyset field so every page is
+as fast as the first one. Pass an empty token to get the first page
+Fails when the last object of a page lacks the keyset field, filter on $exists to page the objects having it
+
+	interface{} filter to query objects by
+
+	Keyset how to walk through the collection
+
+	string token returned with the previous page
+
+	interface{} pointer to a slice to decode the objects into
+
+Returns:
+
+	the token of the next page, empty on the last page - string
+
+	an err - error
+*/
+func (c *Client) PaginateAfter(filter interface{}, keyset Keyset, token string, results interface{}) (_ string, err error) {
+	defer c.observe("paginate", time.Now(), &err)
+	if err := c.collection("paginating objects"); err != nil {
+		return "", err
+	}
+	if keyset.PerPage < 1 {
+		return "", errors.New("perPage must be greater than 0")
+	}
+	if keyset.Field == "" {
+		keyset.Field = "_id"
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return "", err
+	}
+
+	dir, op := 1, "$gt"
+	if keyset.Descending {
+		dir, op = -1, "$lt"
+	}
+	sort := bson.D{{Key: keyset.Field, Value: dir}}
+	if keyset.Field != "_id" {
+		sort = append(sort, bson.E{Key: "_id", Value: dir})
+	}
+	if token != "" {
+		after, err := decodeKeysetToken(token)
+		if err != nil {
+			return "", err
+		}
+		var position bson.D
+		if keyset.Field == "_id" {
+			position = bson.D{{Key: "_id", Value: bson.D{{Key: op, Value: after.ID}}}}
+		} else {
+			position = bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: keyset.Field, Value: bson.D{{Key: op, Value: after.Value}}}},
+				bson.D{{Key: keyset.Field, Value: after.Value}, {Key: "_id", Value: bson.D{{Key: op, Value: after.ID}}}},
+			}}}
+		}
+		if filter == nil {
+			filter = position
+		} else {
+			filter = bson.D{{Key: "$and", Value: bson.A{filter, position}}}
+		}
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	// fetch one more object than needed to know if there is a next page
+	cursor, err := c.co().Find(ctx, filter, c.cf.findOptions(), options.Find().SetSort(sort).SetLimit(keyset.PerPage+1))
+	if err != nil {
+		return "", mapError(err)
+	}
+	var raws []bson.Raw
+	if err := cursor.All(ctx, &raws); err != nil {
+		return "", mapError(err)
+	}
+	next := ""
+	if int64(len(raws)) > keyset.PerPage {
+		raws = raws[:keyset.PerPage]
+		last := raws[len(raws)-1]
+		value, err := last.LookupErr(strings.Split(keyset.Field, ".")...)
+		if err != nil {
+			// a token without the position would restart the walk or skip objects
+			return "", fmt.Errorf("object %v has no keyset field %s", last.Lookup("_id"), keyset.Field)
+		}
+		id, err := last.LookupErr("_id")
+		if err != nil {
+			return "", errors.New("object has no _id, it can't be paged after")
+		}
+		next, err = encodeKeysetToken(keysetToken{Value: value, ID: id})
+		if err != nil {
+			return "", err
+		}
+	}
+	if raws, err = c.redactAll(raws); err != nil {
+		return "", err
+	}
+	return next, decodeAll(c.cf.codecRegistry(), raws, results)
+}
+
+func encodeKeysetToken(token keysetToken) (string, error) {
+	b, err := bson.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeKeysetToken(encoded string) (keysetToken, error) {
+	var token keysetToken
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return token, errors.New("invalid page token")
+	}
+	if err := bson.Unmarshal(b, &token); err != nil {
+		return token, errors.New("invalid page token")
+	}
+	return token, nil
+}
+
+/*
+Decodes a list of raw documents into a pointer to a slice
+
+	[]bson.Raw documents to decode
+
+	interface{} pointer to a slice to decode the documents into
+
+Returns:
+
+	an err - error
+*/
+func decodeAll(reg *bsoncodec.Registry, raws []bson.Raw, results interface{}) error {
+	v := reflect.ValueOf(results)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Slice {
+		return errors.New("results must be a pointer to a slice")
+	}
+	slice := reflect.MakeSlice(v.Elem().Type(), len(raws), len(raws))
+	for i, raw := range raws {
+		if err := bson.UnmarshalWithRegistry(reg, raw, slice.Index(i).Addr().Interface()); err != nil {
+			return err
+		}
+	}
+	v.Elem().Set(slice)
+	return nil
+}
@@ -0,0 +1,73 @@
+package driver
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestKeysetToken(t *testing.T) {
+	oid := primitive.NewObjectID()
+	tests := []struct {
+		name  string
+		value interface{}
+		id    interface{}
+	}{
+		{"string", "ada", oid},
+		{"number", int64(42), oid},
+		{"date", primitive.NewDateTimeFromTime(oid.Timestamp()), "custom-id"},
+		{"null", nil, int32(7)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bson.Marshal(bson.D{{Key: "v", Value: tt.value}, {Key: "id", Value: tt.id}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw := bson.Raw(b)
+			encoded, err := encodeKeysetToken(keysetToken{Value: raw.Lookup("v"), ID: raw.Lookup("id")})
+			if err != nil {
+				t.Fatal(err)
+			}
+			token, err := decodeKeysetToken(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !token.Value.Equal(raw.Lookup("v")) || !token.ID.Equal(raw.Lookup("id")) {
+				t.Errorf("got %v %v, want %v %v", token.Value, token.ID, raw.Lookup("v"), raw.Lookup("id"))
+			}
+		})
+	}
+}
+
+func TestDecodeKeysetTokenInvalid(t *testing.T) {
+	for _, token := range []string{"not base64!", "aGVsbG8"} {
+		if _, err := decodeKeysetToken(token); err == nil {
+			t.Errorf("token %q was accepted", token)
+		}
+	}
+}
+
+func TestDecodeAll(t *testing.T) {
+	var raws []bson.Raw
+	for _, name := range []string{"ada", "grace"} {
+		b, err := bson.Marshal(bson.D{{Key: "name", Value: name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		raws = append(raws, b)
+	}
+	var results []struct {
+		Name string `bson:"name"`
+	}
+	if err := decodeAll(bson.DefaultRegistry, raws, &results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].Name != "ada" || results[1].Name != "grace" {
+		t.Errorf("got %v", results)
+	}
+	if err := decodeAll(bson.DefaultRegistry, raws, results); err == nil {
+		t.Error("a slice that isn't a pointer was accepted")
+	}
+}
@@ -0,0 +1,128 @@
+package driver
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Projection including the fields of a struct, named after their bson tags
+The _id is excluded when the struct has no _id field
+
+	interface{} struct or pointer to a struct
+
+Returns:
+
+	*ProjectionBuilder pointer to a projection builder
+
+	an err - error
+*/
+func ProjectionOf(v interface{}) (*ProjectionBuilder, error) {
+	fields, err := fieldsOf(reflect.TypeOf(v))
+	if err != nil {
+		return nil, err
+	}
+	p := Project()
+	id := false
+	for _, field := range fields {
+		if field == "_id" {
+			id = true
+		}
+		p.Include(field)
+	}
+	if !id {
+		p.Exclude("_id")
+	}
+	return p, nil
+}
+
+/*
+Finds objects of a model decoding only the fields of a partial struct, ex: for list endpoints
+The projection is derived from the partial struct so only its fields are transferred,
+every field of the partial struct has to be a field of the model
+
+	*Client client with the collection of the model set
+
+	interface{} filter to query objects by
+
+	*options.FindOptions options to query the collection with, its projection is overridden
+
+Returns:
+
+	the partial objects - []P
+
+	an err - error, when a field of P is not a field of T
+*/
+func FindPartial[T any, P any](c *Client, filter interface{}, opts *options.FindOptions) ([]P, error) {
+	model, err := fieldsOf(reflect.TypeOf((*T)(nil)))
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{"_id": true}
+	for _, field := range model {
+		known[field] = true
+	}
+	var partial P
+	fields, err := fieldsOf(reflect.TypeOf(partial))
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if !known[field] {
+			return nil, errors.New("field " + field + " of the partial struct is not a field of the model")
+		}
+	}
+	projection, err := ProjectionOf(partial)
+	if err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	var results []P
+	opts = options.MergeFindOptions(opts, options.Find().SetProjection(projection))
+	if err := c.FindAll(filter, &results, opts); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+/*
+Top level bson names of the fields of a struct type, inline structs are flattened
+*/
+func fieldsOf(t reflect.Type) ([]string, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, errors.New("fields can only be listed for a struct")
+	}
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, flags, _ := strings.Cut(field.Tag.Get("bson"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(flags, "inline") {
+			inline, err := fieldsOf(field.Type)
+			if err != nil {
+				continue // inline maps
+			}
+			fields = append(fields, inline...)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name) // default name of the bson codec
+		}
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
@@ -0,0 +1,227 @@
+package driver
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+PipelineBuilder object
+Builds an aggregation pipeline. ex: Pipeline().Match(F("status").Eq("a")).Group("$user", bson.D{{Key: "total", Value: Sum("$amount")}})
+*/
+type PipelineBuilder struct {
+	p mongo.Pipeline
+}
+
+/*
+Creates a new aggregation pipeline
+
+Returns:
+
+	*PipelineBuilder pointer to a pipeline builder
+*/
+func Pipeline() *PipelineBuilder {
+	return &PipelineBuilder{p: mongo.Pipeline{}}
+}
+
+/*
+Adds a raw stage to the pipeline
+
+	string: stage name. ex: $sample
+
+	interface{} stage specification
+*/
+func (p *PipelineBuilder) Stage(name string, spec interface{}) *PipelineBuilder {
+	p.p = append(p.p, bson.D{{Key: name, Value: spec}})
+	return p
+}
+
+/*
+Keeps the documents matching the filter
+*/
+func (p *PipelineBuilder) Match(filter interface{}) *PipelineBuilder {
+	return p.Stage("$match", filter)
+}
+
+/*
+Groups documents by an expression
+
+	interface{} expression to group by, nil to group every document together
+
+	bson.D accumulated fields. ex: bson.D{{Key: "total", Value: Sum("$amount")}}
+*/
+func (p *PipelineBuilder) Group(id interface{}, fields bson.D) *PipelineBuilder {
+	return p.Stage("$group", append(bson.D{{Key: "_id", Value: id}}, fields...))
+}
+
+/*
+Sorts the documents, ex: Sort().Desc("total")
+*/
+func (p *PipelineBuilder) Sort(sort interface{}) *PipelineBuilder {
+	return p.Stage("$sort", sort)
+}
+
+/*
+Keeps the first n documents
+*/
+func (p *PipelineBuilder) Limit(n int64) *PipelineBuilder {
+	return p.Stage("$limit", n)
+}
+
+/*
+Skips the first n documents
+*/
+func (p *PipelineBuilder) Skip(n int64) *PipelineBuilder {
+	return p.Stage("$skip", n)
+}
+
+/*
+Reshapes the documents, ex: Project().Include("name")
+*/
+func (p *PipelineBuilder) Project(projection interface{}) *PipelineBuilder {
+	return p.Stage("$project", projection)
+}
+
+/*
+Adds computed fields to the documents
+*/
+func (p *PipelineBuilder) AddFields(fields bson.D) *PipelineBuilder {
+	return p.Stage("$addFields", fields)
+}
+
+/*
+Outputs a document for every element of an array field
+
+	string: array field
+
+	bool: keep documents where the array is missing or empty
+*/
+func (p *PipelineBuilder) Unwind(field string, preserveEmpty bool) *PipelineBuilder {
+	return p.Stage("$unwind", bson.D{
+		{Key: "path", Value: "$" + field},
+		{Key: "preserveNullAndEmptyArrays", Value: preserveEmpty},
+	})
+}
+
+/*
+Joins documents from another collection with equal fields
+
+	string: collection to join
+
+	string: field of the documents in the pipeline
+
+	string: field of the documents in the joined collection
+
+	string: field to put the joined documents in
+*/
+func (p *PipelineBuilder) Lookup(from string, localField string, foreignField string, as string) *PipelineBuilder {
+	return p.Stage("$lookup", bson.D{
+		{Key: "from", Value: from},
+		{Key: "localField", Value: localField},
+		{Key: "foreignField", Value: foreignField},
+		{Key: "as", Value: as},
+	})
+}
+
+/*
+Joins documents from another collection using a pipeline
+
+	string: collection to join
+
+	bson.D variables available in the pipeline. ex: bson.D{{Key: "user", Value: "$_id"}}
+
+	*PipelineBuilder pipeline to run on the joined collection
+
+	string: field to put the joined documents in
+*/
+func (p *PipelineBuilder) LookupPipeline(from string, let bson.D, pipeline *PipelineBuilder, as string) *PipelineBuilder {
+	spec := bson.D{{Key: "from", Value: from}}
+	if len(let) > 0 {
+		spec = append(spec, bson.E{Key: "let", Value: let})
+	}
+	spec = append(spec, bson.E{Key: "pipeline", Value: pipeline.Stages()}, bson.E{Key: "as", Value: as})
+	return p.Stage("$lookup", spec)
+}
+
+/*
+Runs several pipelines on the same documents
+Each pipeline outputs its results in the field of its name
+
+	map[string]*PipelineBuilder pipelines by output field
+*/
+func (p *PipelineBuilder) Facet(facets map[string]*PipelineBuilder) *PipelineBuilder {
+	names := make([]string, 0, len(facets))
+	for name := range facets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	spec := bson.D{}
+	for _, name := range names {
+		spec = append(spec, bson.E{Key: name, Value: facets[name].Stages()})
+	}
+	return p.Stage("$facet", spec)
+}
+
+/*
+Replaces the documents with a count of them
+
+	string: field to put the count in
+*/
+func (p *PipelineBuilder) Count(field string) *PipelineBuilder {
+	return p.Stage("$count", field)
+}
+
+/*
+Returns the stages of the pipeline
+
+Returns:
+
+	a pipeline - mongo.Pipeline
+*/
+func (p *PipelineBuilder) Stages() mongo.Pipeline {
+	return p.p
+}
+
+/*
+Sums an expression in a $group stage
+*/
+func Sum(expr interface{}) bson.D {
+	return bson.D{{Key: "$sum", Value: expr}}
+}
+
+/*
+Averages an expression in a $group stage
+*/
+func Avg(expr interface{}) bson.D {
+	return bson.D{{Key: "$avg", Value: expr}}
+}
+
+/*
+Smallest value of an expression in a $group stage
+*/
+func Min(expr interface{}) bson.D {
+	return bson.D{{Key: "$min", Value: expr}}
+}
+
+/*
+Largest value of an expression in a $group stage
+*/
+func Max(expr interface{}) bson.D {
+	return bson.D{{Key: "$max", Value: expr}}
+}
+
+/*
+First value of an expression in a $group stage
+*/
+func First(expr interface{}) bson.D {
+	return bson.D{{Key: "$first", Value: expr}}
+}
+
+/*
+Last value of an expression in a $group stage
+*/
+func Last(expr interface{}) bson.D {
+	return bson.D{{Key: "$last", Value: expr}}
+}
@@ -0,0 +1,74 @@
+package driver
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPipeline(t *testing.T) {
+	tests := []struct {
+		name     string
+		pipeline *PipelineBuilder
+		want     string
+	}{
+		{"empty", Pipeline(), `{"p":[]}`},
+		{
+			"match group sort",
+			Pipeline().Match(F("status").Eq("a")).Group("$user", bson.D{{Key: "total", Value: Sum("$amount")}}).Sort(Sort().Desc("total")),
+			`{"p":[{"$match":{"status":{"$eq":"a"}}},{"$group":{"_id":"$user","total":{"$sum":"$amount"}}},{"$sort":{"total":-1}}]}`,
+		},
+		{"skip limit", Pipeline().Skip(20).Limit(10), `{"p":[{"$skip":20},{"$limit":10}]}`},
+		{"project", Pipeline().Project(Project().Include("name").Exclude("_id")), `{"p":[{"$project":{"name":1,"_id":0}}]}`},
+		{"unwind", Pipeline().Unwind("tags", true), `{"p":[{"$unwind":{"path":"$tags","preserveNullAndEmptyArrays":true}}]}`},
+		{
+			"lookup",
+			Pipeline().Lookup("users", "user", "_id", "user"),
+			`{"p":[{"$lookup":{"from":"users","localField":"user","foreignField":"_id","as":"user"}}]}`,
+		},
+		{
+			"lookup pipeline without let",
+			Pipeline().LookupPipeline("orders", nil, Pipeline().Limit(1), "last"),
+			`{"p":[{"$lookup":{"from":"orders","pipeline":[{"$limit":1}],"as":"last"}}]}`,
+		},
+		{
+			"facets are sorted by name",
+			Pipeline().Facet(map[string]*PipelineBuilder{"total": Pipeline().Count("n"), "items": Pipeline().Limit(2)}),
+			`{"p":[{"$facet":{"items":[{"$limit":2}],"total":[{"$count":"n"}]}}]}`,
+		},
+		{"out", Pipeline().Out("daily"), `{"p":[{"$out":"daily"}]}`},
+		{"merge", Pipeline().Merge(MergeOptions{Into: "daily"}), `{"p":[{"$merge":{"into":"daily"}}]}`},
+		{
+			"merge into another database",
+			Pipeline().Merge(MergeOptions{Into: "daily", Database: "reports", On: []string{"day", "shop"}, WhenMatched: MergeReplace, WhenNotMatched: MergeDiscard}),
+			`{"p":[{"$merge":{"into":{"db":"reports","coll":"daily"},"on":["day","shop"],"whenMatched":"replace","whenNotMatched":"discard"}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extJSON(bson.D{{Key: "p", Value: tt.pipeline.Stages()}}); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortAndProject(t *testing.T) {
+	tests := []struct {
+		name string
+		d    bson.D
+		want string
+	}{
+		{"sort", Sort().Asc("a", "b").Desc("c").D(), `{"a":1,"b":1,"c":-1}`},
+		{"sort by score", Sort().TextScore("score").D(), `{"score":{"$meta":"textScore"}}`},
+		{"slice", Project().Include("name").Slice("comments", -5).D(), `{"name":1,"comments":{"$slice":-5}}`},
+		{"project score", Project().TextScore("score").D(), `{"score":{"$meta":"textScore"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extJSON(tt.d); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package driver
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Pool object
+Tunes the connection pool kept to each server, zero values keep the driver defaults
+
+	MinSize: connections kept open even when idle
+
+	MaxSize: most connections open at once, the driver default is 100
+
+	MaxIdleTime: how long a connection can stay idle before it is closed
+
+	MaxConnecting: most connections being established at once, the driver default is 2
+*/
+type Pool struct {
+	MinSize       uint64
+	MaxSize       uint64
+	MaxIdleTime   time.Duration
+	MaxConnecting uint64
+}
+
+/*
+PoolStats object
+Snapshot of the connection pools of every server
+
+	Open: connections currently open
+
+	InUse: connections checked out by operations
+
+	Idle: connections open and waiting in the pools
+
+	Waiting: operations waiting for a connection
+
+	Created: connections opened since the client connected
+
+	Closed: connections closed since the client connected
+
+	Failed: checkouts that failed, ex: when the pool is full for too long
+*/
+type PoolStats struct {
+	Open    int64
+	InUse   int64
+	Idle    int64
+	Waiting int64
+	Created int64
+	Closed  int64
+	Failed  int64
+}
+
+/*
+Counters of the pool events
+*/
+type poolCounters struct {
+	mu    sync.Mutex
+	stats PoolStats
+}
+
+/*
+Sets the connection pool settings, they apply when the client connects
+
+	Pool: pool settings
+
+Returns:
+
+	an option - Option
+*/
+func WithPool(pool Pool) Option {
+	return func(cf *config) {
+		cf.pool = &pool
+	}
+}
+
+/*
+Returns a snapshot of the connection pools
+
+Returns:
+
+	the stats - PoolStats
+*/
+func (c *Client) PoolStats() PoolStats {
+	c.cn.pool.mu.Lock()
+	defer c.cn.pool.mu.Unlock()
+	stats := c.cn.pool.stats
+	stats.Idle = stats.Open - stats.InUse
+	return stats
+}
+
+/*
+Applies the pool settings to the client options
+*/
+func (p *Pool) apply(opts *options.ClientOptions) {
+	if p.MinSize > 0 {
+		opts.SetMinPoolSize(p.MinSize)
+	}
+	if p.MaxSize > 0 {
+		opts.SetMaxPoolSize(p.MaxSize)
+	}
+	if p.MaxIdleTime > 0 {
+		opts.SetMaxConnIdleTime(p.MaxIdleTime)
+	}
+	if p.MaxConnecting > 0 {
+		opts.SetMaxConnecting(p.MaxConnecting)
+	}
+}
+
+/*
+Monitor counting the pool events for PoolStats
+*/
+func (cn *connection) poolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			cn.pool.mu.Lock()
+			defer cn.pool.mu.Unlock()
+			s := &cn.pool.stats
+			switch e.Type {
+			case event.ConnectionCreated:
+				s.Open++
+				s.Created++
+			case event.ConnectionClosed:
+				s.Open--
+				s.Closed++
+			case event.GetStarted:
+				s.Waiting++
+			case event.GetSucceeded:
+				s.Waiting--
+				s.InUse++
+			case event.GetFailed:
+				s.Waiting--
+				s.Failed++
+			case event.ConnectionReturned:
+				s.InUse--
+			}
+		},
+	}
+}
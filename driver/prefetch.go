@@ -0,0 +1,193 @@
+package driver
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+IterateOptions object
+Configures Iterate
+
+	BatchSize: number of documents per batch, the server default when 0
+
+	Prefetch: number of batches fetched ahead of the caller, 1 when 0
+
+	Sort: order of the documents, can be nil
+
+	Projection: fields of the documents, can be nil
+*/
+type IterateOptions struct {
+	BatchSize  int32
+	Prefetch   int
+	Sort       interface{}
+	Projection interface{}
+}
+
+/*
+Iterator object
+Iterates over the documents of a find while the next batches are fetched in the background
+*/
+type Iterator struct {
+	batches chan []bson.Raw
+	batch   []bson.Raw
+	current bson.Raw
+	err     error
+	errc    chan error
+	cancel  context.CancelFunc
+	c       *Client
+}
+
+/*
+Finds the documents matching a filter and iterates over them, fetching the next
+batches in a background goroutine while the caller processes the current one
+The iteration isn't bounded by the timeout of the client, fetching each batch is.
+Meant for large scans where the network latency would dominate. ex:
+
+	it, err := c.Iterate(filter, IterateOptions{BatchSize: 1000})
+	defer it.Close()
+	for it.Next() { it.Decode(&doc) }
+	return it.Err()
+
+	interface{} filter to query objects by
+
+	IterateOptions options of the iteration
+
+Returns:
+
+	*Iterator pointer to an iterator
+
+	an err - error
+*/
+func (c *Client) Iterate(filter interface{}, opts IterateOptions) (_ *Iterator, err error) {
+	defer c.observe("iterate", time.Now(), &err)
+	if err := c.collection("iterating over objects"); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.stream()
+	// ping database
+	if err := c.ping(); err != nil {
+		cancel()
+		return nil, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	find := options.Find()
+	if opts.BatchSize > 0 {
+		find.SetBatchSize(opts.BatchSize)
+	}
+	if opts.Sort != nil {
+		find.SetSort(opts.Sort)
+	}
+	if opts.Projection != nil {
+		find.SetProjection(opts.Projection)
+	}
+	if opts.Prefetch <= 0 {
+		opts.Prefetch = 1
+	}
+	var cursor *mongo.Cursor
+	err = c.first(ctx, func(ctx context.Context) (err error) {
+		cursor, err = c.co().Find(ctx, filter, c.cf.findOptions(), find)
+		return err
+	})
+	if err != nil {
+		cancel()
+		return nil, mapError(err)
+	}
+	it := &Iterator{
+		batches: make(chan []bson.Raw, opts.Prefetch),
+		errc:    make(chan error, 1),
+		cancel:  cancel,
+		c:       c,
+	}
+	read := c.reader()
+	go func() {
+		defer close(it.batches)
+		defer cancel() // Shutdown doesn't wait for the caller to close the iterator
+		defer cursor.Close(context.Background())
+		var batch []bson.Raw
+		for c.next(ctx, cursor) {
+			doc := append(bson.Raw(nil), cursor.Current...)
+			if read != nil {
+				var err error
+				if doc, err = read(doc); err != nil {
+					it.errc <- err
+					return
+				}
+			}
+			batch = append(batch, doc)
+			// hand over the batch before Next asks the server for the next one
+			if cursor.RemainingBatchLength() == 0 {
+				select {
+				case it.batches <- batch:
+				case <-ctx.Done():
+					return
+				}
+				batch = nil
+			}
+		}
+		if err := cursor.Err(); err != nil && ctx.Err() == nil {
+			it.errc <- mapError(err)
+		}
+	}()
+	return it, nil
+}
+
+/*
+Moves to the next document
+
+Returns:
+
+	false when there are no more documents or the iteration failed, see Err - bool
+*/
+func (it *Iterator) Next() bool {
+	for len(it.batch) == 0 {
+		batch, ok := <-it.batches
+		if !ok {
+			select {
+			case it.err = <-it.errc:
+			default:
+			}
+			it.current = nil
+			return false
+		}
+		it.batch = batch
+	}
+	it.current, it.batch = it.batch[0], it.batch[1:]
+	return true
+}
+
+/*
+Returns the current document
+*/
+func (it *Iterator) Current() bson.Raw {
+	return it.current
+}
+
+/*
+Decodes the current document into v
+*/
+func (it *Iterator) Decode(v interface{}) error {
+	return bson.UnmarshalWithRegistry(it.c.cf.codecRegistry(), it.current, v)
+}
+
+/*
+Returns the error that stopped the iteration, nil when it reached the end
+*/
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+/*
+Stops the iteration and closes the cursor, safe to call more than once
+*/
+func (it *Iterator) Close() {
+	it.cancel()
+	for range it.batches { // let the fetching goroutine exit
+	}
+}
@@ -0,0 +1,134 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ProfilingOff  = 0 // profile nothing
+	ProfilingSlow = 1 // profile operations slower than slowms
+	ProfilingAll  = 2 // profile every operation
+)
+
+/*
+ProfilingStatus object
+Contains the profiler settings of a database
+*/
+type ProfilingStatus struct {
+	Level  int     `bson:"was" json:"level"`
+	SlowMS int     `bson:"slowms" json:"slowms"`
+	Sample float64 `bson:"sampleRate" json:"sampleRate"`
+}
+
+/*
+ProfiledOperation object
+Contains the most useful fields of a system.profile entry, Raw holds the entry
+*/
+type ProfiledOperation struct {
+	Op           string    `bson:"op" json:"op"`
+	Namespace    string    `bson:"ns" json:"ns"`
+	Command      bson.Raw  `bson:"command" json:"command"`
+	Millis       int64     `bson:"millis" json:"millis"`
+	DocsExamined int64     `bson:"docsExamined" json:"docsExamined"`
+	KeysExamined int64     `bson:"keysExamined" json:"keysExamined"`
+	Returned     int64     `bson:"nreturned" json:"nreturned"`
+	PlanSummary  string    `bson:"planSummary" json:"planSummary"`
+	Timestamp    time.Time `bson:"ts" json:"ts"`
+	Raw          bson.Raw  `bson:"-" json:"-"`
+}
+
+/*
+Sets the profiling level of the database that is set
+
+	int: ProfilingOff, ProfilingSlow or ProfilingAll
+
+	int: threshold in milliseconds above which operations are slow, 0 to keep the current one
+
+Returns:
+
+	the previous settings - *ProfilingStatus
+
+	an err - error
+*/
+func (c *Client) SetProfilingLevel(level int, slowms int) (*ProfilingStatus, error) {
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	cmd := bson.D{{Key: "profile", Value: level}}
+	if slowms > 0 {
+		cmd = append(cmd, bson.E{Key: "slowms", Value: slowms})
+	}
+	if c.cf.dryRun != nil {
+		return nil, c.dryRunChange("setProfilingLevel", cmd)
+	}
+	var status ProfilingStatus
+	if err := c.RunCommand("", cmd, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+/*
+Returns the profiler settings of the database that is set
+
+Returns:
+
+	the settings - *ProfilingStatus
+
+	an err - error
+*/
+func (c *Client) ProfilingLevel() (*ProfilingStatus, error) {
+	var status ProfilingStatus
+	if err := c.RunCommand("", bson.D{{Key: "profile", Value: -1}}, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+/*
+Finds profiled operations of the database that is set, newest first
+ex: slow queries on a collection F("ns").Eq("app.users").And(F("millis").Gt(100))
+
+	interface{} filter on the system.profile entries, nil for every entry
+
+	int64 maximum number of entries, 0 for no limit
+
+Returns:
+
+	the operations - []ProfiledOperation
+
+	an err - error
+*/
+func (c *Client) QueryProfile(filter interface{}, limit int64) (_ []ProfiledOperation, err error) {
+	defer c.observe("queryProfile", time.Now(), &err)
+	if c.db() == nil {
+		return nil, noDatabase("querying the profiler")
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+	opts := options.Find().SetSort(Sort().Desc("ts"))
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	cursor, err := c.db().Collection("system.profile").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+	var operations []ProfiledOperation
+	for cursor.Next(ctx) {
+		var op ProfiledOperation
+		if err := cursor.Decode(&op); err != nil {
+			return nil, err
+		}
+		op.Raw = append(bson.Raw(nil), cursor.Current...)
+		operations = append(operations, op)
+	}
+	return operations, mapError(cursor.Err())
+}
@@ -0,0 +1,168 @@
+package driver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+SubscribeOptions object
+Configures a subscription
+
+	Workers: number of events handled at the same time, events are handled in order when 0 or 1
+
+	OnError: called when the handler fails on an event or the change stream fails, the event is empty in that case
+*/
+type SubscribeOptions struct {
+	Workers int
+	OnError func(error, ChangeEvent)
+}
+
+/*
+Subscription object
+Delivers the changes of a collection to a handler in the background
+*/
+type Subscription struct {
+	c      *Client
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+/*
+Keeps the resume token of a subscription for as long as it runs
+*/
+type memoryTokenStore struct {
+	mu    sync.Mutex
+	token bson.Raw
+}
+
+func (s *memoryTokenStore) Load(ctx context.Context, name string) (bson.Raw, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token, nil
+}
+
+func (s *memoryTokenStore) Save(ctx context.Context, name string, token bson.Raw) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+	return nil
+}
+
+/*
+Subscribes a handler to the changes of a collection of the database that is set
+Every subscription receives every change matching its filter, so several
+handlers can subscribe to the same collection. Each subscription opens its own
+change stream which resumes after errors. Use Watch to resume after a restart
+
+	string: name of the collection
+
+	interface{} filter on the change events, nil for every change. ex: bson.D{{Key: "operationType", Value: "insert"}}
+
+	func(ChangeEvent) error handler called for every change
+
+	*SubscribeOptions options of the subscription, nil for the defaults
+
+Returns:
+
+	*Subscription pointer to a subscription
+
+	an err - error
+*/
+func (c *Client) Subscribe(collection string, filter interface{}, handler func(ChangeEvent) error, opts *SubscribeOptions) (*Subscription, error) {
+	if c.db() == nil {
+		return nil, noDatabase("subscribing to a collection")
+	}
+	if opts == nil {
+		opts = &SubscribeOptions{}
+	}
+	client := c.With()
+	client.SetCollection(collection)
+	var pipeline mongo.Pipeline
+	if filter != nil {
+		pipeline = Pipeline().Match(filter).Stages()
+	}
+	w, err := client.Watch(collection, pipeline, &memoryTokenStore{})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Subscription{c: c, cancel: cancel, done: make(chan struct{})}
+	go s.run(ctx, w, handler, opts)
+	c.cn.onShutdown(s, func() error {
+		s.stop()
+		return nil
+	})
+	return s, nil
+}
+
+/*
+Stops the subscription, waiting for the events being handled
+*/
+func (s *Subscription) Close() {
+	s.c.cn.forget(s)
+	s.stop()
+}
+
+func (s *Subscription) stop() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *Subscription) run(ctx context.Context, w *Watcher, handler func(ChangeEvent) error, opts *SubscribeOptions) {
+	defer close(s.done)
+
+	handle := func(event ChangeEvent) {
+		if err := handler(event); err != nil && opts.OnError != nil {
+			opts.OnError(err, event)
+		}
+	}
+	deliver := func(event ChangeEvent) error {
+		handle(event)
+		return nil
+	}
+	if opts.Workers > 1 {
+		events := make(chan ChangeEvent)
+		var wg sync.WaitGroup
+		for i := 0; i < opts.Workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for event := range events {
+					handle(event)
+				}
+			}()
+		}
+		defer wg.Wait()
+		defer close(events)
+		deliver = func(event ChangeEvent) error {
+			select {
+			case events <- event:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	for {
+		err := w.Run(ctx, deliver)
+		if ctx.Err() != nil {
+			return
+		}
+		if opts.OnError != nil {
+			opts.OnError(err, ChangeEvent{})
+		}
+		// wait before opening the change stream again
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package driver
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Queryable Encryption algorithms of explicitly encrypted fields
+*/
+const (
+	AlgorithmIndexed   = "Indexed"   // can be queried with the query types of the field
+	AlgorithmUnindexed = "Unindexed" // can't be queried
+)
+
+/*
+Query types of Queryable Encryption fields
+*/
+const (
+	QueryEquality = "equality"
+	QueryRange    = "range" // needs MongoDB 7.0 and a libmongocrypt supporting it
+)
+
+/*
+EncryptedField object
+A field of a collection encrypted with Queryable Encryption
+
+	Path: dotted path of the field
+
+	BsonType: BSON type of the values. ex: "string", "int", "date"
+
+	KeyID: data key encrypting the field, created by CreateEncryptedCollection when nil
+
+	Queries: queries the field supports, none when empty
+*/
+type EncryptedField struct {
+	Path     string
+	BsonType string
+	KeyID    *primitive.Binary
+	Queries  []EncryptedQuery
+}
+
+/*
+EncryptedQuery object
+A query supported on an encrypted field
+
+	Type: QueryEquality or QueryRange
+
+	Contention: contention factor, higher values resist frequency analysis better but slow down queries
+
+	Min: smallest value of a range field
+
+	Max: largest value of a range field
+
+	Sparsity: lower values make range queries faster and the data larger
+*/
+type EncryptedQuery struct {
+	Type       string
+	Contention int64
+	Min        interface{}
+	Max        interface{}
+	Sparsity   int64
+}
+
+/*
+Builds the encryptedFields document of a collection
+The result can be used in AutoEncryption.EncryptedFieldsMap
+
+	...EncryptedField: encrypted fields of the collection
+
+Returns:
+
+	a document - bson.D
+*/
+func EncryptedFields(fields ...EncryptedField) bson.D {
+	a := bson.A{}
+	for _, field := range fields {
+		spec := bson.D{{Key: "path", Value: field.Path}, {Key: "bsonType", Value: field.BsonType}}
+		if field.KeyID != nil {
+			spec = append(spec, bson.E{Key: "keyId", Value: *field.KeyID})
+		}
+		if len(field.Queries) > 0 {
+			queries := bson.A{}
+			for _, q := range field.Queries {
+				query := bson.D{{Key: "queryType", Value: q.Type}}
+				if q.Contention > 0 {
+					query = append(query, bson.E{Key: "contention", Value: q.Contention})
+				}
+				if q.Min != nil {
+					query = append(query, bson.E{Key: "min", Value: q.Min})
+				}
+				if q.Max != nil {
+					query = append(query, bson.E{Key: "max", Value: q.Max})
+				}
+				if q.Sparsity > 0 {
+					query = append(query, bson.E{Key: "sparsity", Value: q.Sparsity})
+				}
+				queries = append(queries, query)
+			}
+			spec = append(spec, bson.E{Key: "queries", Value: queries})
+		}
+		a = append(a, spec)
+	}
+	return bson.D{{Key: "fields", Value: a}}
+}
+
+/*
+Creates a collection encrypted with Queryable Encryption
+A data key is created for every field without a KeyID. Once the client is
+connected with the collection in AutoEncryption.EncryptedFieldsMap, values
+are encrypted on insert and the find methods can query the fields with
+the query types they support
+
+	string: name of the collection
+
+	*KeyVault key vault to create the data keys in
+
+	string: KMS provider protecting the data keys. ex: "local"
+
+	interface{} master key of the provider, nil for "local"
+
+	...EncryptedField: encrypted fields of the collection
+
+Returns:
+
+	the encrypted fields with their key ids, to put in EncryptedFieldsMap - bson.D
+
+	an err - error
+*/
+func (c *Client) CreateEncryptedCollection(name string, kv *KeyVault, provider string, masterKey interface{}, fields ...EncryptedField) (bson.D, error) {
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("encrypted collections need at least one field")
+	}
+	for i := range fields {
+		if fields[i].KeyID != nil {
+			continue
+		}
+		id, err := kv.CreateDataKey(provider, masterKey)
+		if err != nil {
+			return nil, err
+		}
+		fields[i].KeyID = &id
+	}
+	encrypted := EncryptedFields(fields...)
+	if err := c.CreateCollection(name, options.CreateCollection().SetEncryptedFields(encrypted)); err != nil {
+		return nil, err
+	}
+	return encrypted, nil
+}
+
+/*
+Encrypts a value for an indexed Queryable Encryption field
+Only needed when automatic encryption is bypassed
+
+	interface{} value to encrypt
+
+	primitive.Binary id of the data key of the field
+
+	int64 contention factor of the field
+
+Returns:
+
+	the encrypted value - primitive.Binary
+
+	an err - error
+*/
+func (k *KeyVault) EncryptIndexed(value interface{}, keyID primitive.Binary, contention int64) (primitive.Binary, error) {
+	t, b, err := bson.MarshalValue(value)
+	if err != nil {
+		return primitive.Binary{}, err
+	}
+	return k.ce.Encrypt(context.Background(), bson.RawValue{Type: t, Value: b}, options.Encrypt().
+		SetKeyID(keyID).
+		SetAlgorithm(AlgorithmIndexed).
+		SetQueryType(QueryEquality).
+		SetContentionFactor(contention))
+}
@@ -0,0 +1,140 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+/*
+ReconnectPolicy object
+Describes how a client reconnects once a ping to the primary fails
+
+	Attempts: number of attempts before giving up, 5 when 0
+
+	Backoff: wait before the first attempt, doubled after each one. 1s when 0
+
+	MaxBackoff: longest wait between two attempts, maxMonitorBackoff when 0
+*/
+type ReconnectPolicy struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+/*
+Reconnects in the background when a ping to the primary fails
+Each attempt first checks if the driver recovered by itself and otherwise
+replaces the client with a new one, disconnecting the stale one. The state goes
+to StateReconnecting, then StateConnected or StateDisconnected once the attempts
+run out, see OnStateChange
+
+	ReconnectPolicy policy to reconnect with
+
+Returns:
+
+	an option - Option
+*/
+func WithReconnect(policy ReconnectPolicy) Option {
+	return func(cf *config) {
+		cf.reconnect = &policy
+	}
+}
+
+/*
+Replaces the connection of the client and of every client created from it
+The stale client is disconnected once the new one is connected. Operations
+in flight on the stale client fail and can be retried
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) Reconnect() error {
+	if c.cn.closing() {
+		return ErrShutdown
+	}
+	c.cn.set(StateReconnecting)
+	return c.Connect()
+}
+
+/*
+Reconnects with the backoff of the policy
+Only one reconnection runs at a time for the clients sharing the connection
+*/
+func (c *Client) reconnect(policy ReconnectPolicy) {
+	c.cn.mu.Lock()
+	if c.cn.redialing {
+		c.cn.mu.Unlock()
+		return
+	}
+	c.cn.redialing = true
+	c.cn.mu.Unlock()
+	defer func() {
+		c.cn.mu.Lock()
+		c.cn.redialing = false
+		c.cn.mu.Unlock()
+	}()
+
+	attempts, wait, max := policy.Attempts, policy.Backoff, policy.MaxBackoff
+	if attempts <= 0 {
+		attempts = 5
+	}
+	if wait <= 0 {
+		wait = time.Second
+	}
+	if max <= 0 {
+		max = maxMonitorBackoff
+	}
+	for i := 0; i < attempts; i++ {
+		time.Sleep(wait)
+		if c.cn.closing() {
+			return
+		}
+		// the driver reconnects by itself when the cluster comes back
+		if cl := c.cn.client(); cl != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), wait)
+			err := cl.Ping(ctx, readpref.Primary())
+			cancel()
+			if err == nil {
+				c.cn.set(StateConnected)
+				return
+			}
+		}
+		if err := c.Reconnect(); err == nil {
+			return
+		}
+		c.cn.set(StateReconnecting)
+		if wait *= 2; wait > max {
+			wait = max
+		}
+	}
+	c.cn.set(StateDisconnected)
+}
+
+/*
+Points the client to the connection shared with its copies
+Database and collection handles are made again when the connection was replaced
+*/
+func (c *Client) rebind() error {
+	cl := c.cn.client()
+	if cl == nil {
+		return errors.New("client is not connected")
+	}
+	h := c.hs.Load()
+	if h != nil && h.cl == cl {
+		return nil
+	}
+	bound := &handles{cl: cl}
+	if h != nil && h.db != nil {
+		bound.db = cl.Database(h.db.Name(), c.cf.databaseOptions())
+		if h.co != nil {
+			bound.co = bound.db.Collection(h.co.Name(), c.cf.collectionOptions())
+		}
+	}
+	// a concurrent rebind or SetCollection published first, keep theirs
+	c.hs.CompareAndSwap(h, bound)
+	return nil
+}
@@ -0,0 +1,263 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+RedactRule object
+A field hidden from read results unless the caller holds a permission
+
+	Path: dotted path of the field, fields of arrays of documents are redacted in every element
+
+	Mask: value replacing the field, the field is removed when nil. ex: "***"
+
+	Permission: permission allowing to read the field, given with WithPermissions. Empty to always redact it
+*/
+type RedactRule struct {
+	Path       string
+	Mask       interface{}
+	Permission string
+}
+
+/*
+Redacts fields from the documents returned by the find, aggregate and search methods
+Redaction happens on the client after the documents are received, use a
+projection to keep sensitive fields from leaving the server
+
+	...RedactRule: fields to redact
+
+Returns:
+
+	an option - Option
+*/
+func WithRedaction(rules ...RedactRule) Option {
+	return func(cf *config) {
+		cf.redactions = append(cf.redactions[:len(cf.redactions):len(cf.redactions)], rules...)
+	}
+}
+
+/*
+Grants permissions to read redacted fields
+Meant to be used for the operations of a trusted caller. ex: c.With(WithPermissions("pii")).FindByID(id, &user)
+
+	...string: permissions of the caller
+
+Returns:
+
+	an option - Option
+*/
+func WithPermissions(permissions ...string) Option {
+	return func(cf *config) {
+		cf.permissions = append(cf.permissions[:len(cf.permissions):len(cf.permissions)], permissions...)
+	}
+}
+
+/*
+Rules that apply to the caller, nil when nothing has to be redacted
+*/
+func (cf *config) activeRedactions() []RedactRule {
+	var rules []RedactRule
+	for _, rule := range cf.redactions {
+		granted := false
+		for _, permission := range cf.permissions {
+			if rule.Permission != "" && rule.Permission == permission {
+				granted = true
+				break
+			}
+		}
+		if !granted {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+/*
+Redacts a document
+
+	bson.Raw document to redact
+
+	[]RedactRule fields to redact
+
+Returns:
+
+	the redacted document - bson.Raw
+
+	an err - error
+*/
+func Redact(doc bson.Raw, rules []RedactRule) (bson.Raw, error) {
+	if len(rules) == 0 {
+		return doc, nil
+	}
+	var d bson.D
+	if err := bson.Unmarshal(doc, &d); err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		d = redactPath(d, strings.Split(rule.Path, "."), rule.Mask)
+	}
+	return bson.Marshal(d)
+}
+
+func redactPath(d bson.D, path []string, mask interface{}) bson.D {
+	for i := range d {
+		if d[i].Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			if mask == nil {
+				return append(d[:i:i], d[i+1:]...)
+			}
+			d[i].Value = mask
+			return d
+		}
+		d[i].Value = redactValue(d[i].Value, path[1:], mask)
+		return d
+	}
+	return d
+}
+
+func redactValue(v interface{}, path []string, mask interface{}) interface{} {
+	switch v := v.(type) {
+	case bson.D:
+		return redactPath(v, path, mask)
+	case bson.A:
+		for i := range v {
+			v[i] = redactValue(v[i], path, mask)
+		}
+		return v
+	}
+	return v
+}
+
+/*
+Prepares the documents read before they are returned to the caller, putting
+back the fields moved to GridFS and redacting them. nil when there is nothing to do
+*/
+func (c *Client) reader() func(bson.Raw) (bson.Raw, error) {
+	rules := c.cf.activeRedactions()
+	overflow := c.cf.size != nil && c.cf.size.Policy == SizeOverflow
+	if rules == nil && !overflow {
+		return nil
+	}
+	return func(doc bson.Raw) (bson.Raw, error) {
+		if overflow {
+			var err error
+			if doc, err = c.rehydrate(doc); err != nil {
+				return nil, err
+			}
+		}
+		return Redact(doc, rules)
+	}
+}
+
+/*
+Redacts the distinct values of a field
+A redacted field, or a field inside one, can't be read. The fields
+redacted inside the field are redacted from each value
+*/
+func (c *Client) redactDistinct(field string, values []interface{}) ([]interface{}, error) {
+	for _, rule := range c.cf.activeRedactions() {
+		if field == rule.Path || strings.HasPrefix(field, rule.Path+".") {
+			return nil, errors.New("field " + field + " is redacted")
+		}
+		if strings.HasPrefix(rule.Path, field+".") {
+			path := strings.Split(strings.TrimPrefix(rule.Path, field+"."), ".")
+			for i := range values {
+				values[i] = redactValue(values[i], path, rule.Mask)
+			}
+		}
+	}
+	return values, nil
+}
+
+/*
+Redacts the documents of a cursor and decodes them into results
+*/
+func (c *Client) all(ctx context.Context, cursor *mongo.Cursor, results interface{}) error {
+	read := c.reader()
+	if read == nil {
+		return mapError(cursor.All(ctx, results))
+	}
+	raws, err := redactCursor(ctx, cursor, read)
+	if err != nil {
+		return mapError(err)
+	}
+	return decodeAll(c.cf.codecRegistry(), raws, results)
+}
+
+/*
+Redacts the documents of a cursor returned to the caller
+The documents are read at once when something has to be redacted
+*/
+func (c *Client) redactCursor(ctx context.Context, cursor *mongo.Cursor) (*mongo.Cursor, error) {
+	read := c.reader()
+	if read == nil {
+		return cursor, nil
+	}
+	raws, err := redactCursor(ctx, cursor, read)
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]interface{}, 0, len(raws))
+	for _, raw := range raws {
+		docs = append(docs, raw)
+	}
+	return mongo.NewCursorFromDocuments(docs, nil, nil)
+}
+
+/*
+Redacts the document of a single result returned to the caller
+*/
+func (c *Client) redactResult(res *mongo.SingleResult) *mongo.SingleResult {
+	read := c.reader()
+	if read == nil {
+		return res
+	}
+	raw, err := res.DecodeBytes()
+	if err != nil {
+		return res
+	}
+	raw, err = read(raw)
+	if err != nil {
+		return errorResult(err)
+	}
+	return mongo.NewSingleResultFromDocument(raw, nil, nil)
+}
+
+/*
+Redacts raw documents
+*/
+func (c *Client) redactAll(raws []bson.Raw) ([]bson.Raw, error) {
+	read := c.reader()
+	if read == nil {
+		return raws, nil
+	}
+	for i := range raws {
+		raw, err := read(raws[i])
+		if err != nil {
+			return nil, err
+		}
+		raws[i] = raw
+	}
+	return raws, nil
+}
+
+func redactCursor(ctx context.Context, cursor *mongo.Cursor, read func(bson.Raw) (bson.Raw, error)) ([]bson.Raw, error) {
+	defer cursor.Close(ctx)
+	var raws []bson.Raw
+	for cursor.Next(ctx) {
+		raw, err := read(cursor.Current)
+		if err != nil {
+			return nil, err
+		}
+		raws = append(raws, raw)
+	}
+	return raws, cursor.Err()
+}
@@ -0,0 +1,112 @@
+package driver
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestRedact(t *testing.T) {
+	doc := bson.D{
+		{Key: "name", Value: "ada"},
+		{Key: "ssn", Value: "123-45-6789"},
+		{Key: "card", Value: bson.D{{Key: "number", Value: "4242"}, {Key: "brand", Value: "visa"}}},
+		{Key: "orders", Value: bson.A{
+			bson.D{{Key: "address", Value: "1 main st"}},
+			bson.D{{Key: "address", Value: "2 main st"}},
+		}},
+	}
+
+	tests := []struct {
+		name  string
+		rules []RedactRule
+		want  string
+	}{
+		{"no rules", nil, extJSON(doc)},
+		{"removed", []RedactRule{{Path: "ssn"}}, `{"name":"ada","card":{"number":"4242","brand":"visa"},"orders":[{"address":"1 main st"},{"address":"2 main st"}]}`},
+		{"masked", []RedactRule{{Path: "ssn", Mask: "***"}}, `{"name":"ada","ssn":"***","card":{"number":"4242","brand":"visa"},"orders":[{"address":"1 main st"},{"address":"2 main st"}]}`},
+		{"nested", []RedactRule{{Path: "card.number", Mask: "***"}}, `{"name":"ada","ssn":"123-45-6789","card":{"number":"***","brand":"visa"},"orders":[{"address":"1 main st"},{"address":"2 main st"}]}`},
+		{"arrays of documents", []RedactRule{{Path: "orders.address"}}, `{"name":"ada","ssn":"123-45-6789","card":{"number":"4242","brand":"visa"},"orders":[{},{}]}`},
+		{"missing", []RedactRule{{Path: "phone"}}, extJSON(doc)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bson.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, err := Redact(b, tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := extJSON(raw); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActiveRedactions(t *testing.T) {
+	rules := []RedactRule{
+		{Path: "ssn", Permission: "pii"},
+		{Path: "card", Permission: "billing"},
+		{Path: "password"},
+	}
+
+	tests := []struct {
+		name        string
+		permissions []string
+		want        []string
+	}{
+		{"without permissions", nil, []string{"ssn", "card", "password"}},
+		{"one permission", []string{"pii"}, []string{"card", "password"}},
+		{"every permission", []string{"pii", "billing"}, []string{"password"}},
+		{"an empty permission grants nothing", []string{""}, []string{"ssn", "card", "password"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cf := newConfig([]Option{WithRedaction(rules...), WithPermissions(tt.permissions...)})
+			var got []string
+			for _, rule := range cf.activeRedactions() {
+				got = append(got, rule.Path)
+			}
+			if extJSON(bson.D{{Key: "p", Value: got}}) != extJSON(bson.D{{Key: "p", Value: tt.want}}) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactDistinct(t *testing.T) {
+	c := &Client{cf: newConfig([]Option{WithRedaction(
+		RedactRule{Path: "ssn"},
+		RedactRule{Path: "card.number", Mask: "***"},
+	)})}
+
+	tests := []struct {
+		name    string
+		field   string
+		values  []interface{}
+		want    string
+		wantErr bool
+	}{
+		{"other field", "name", []interface{}{"ada"}, `{"v":["ada"]}`, false},
+		{"redacted field", "ssn", []interface{}{"123"}, "", true},
+		{"inside a redacted field", "card.number", []interface{}{"4242"}, "", true},
+		{"field holding a redacted field", "card", []interface{}{bson.D{{Key: "number", Value: "4242"}, {Key: "brand", Value: "visa"}}}, `{"v":[{"number":"***","brand":"visa"}]}`, false},
+		{"prefix that isn't a parent", "ssnType", []interface{}{"a"}, `{"v":["a"]}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := c.redactDistinct(tt.field, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got err %v, want an error %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if got := extJSON(bson.D{{Key: "v", Value: values}}); got != tt.want {
+					t.Errorf("got %s, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+/*
+Registry object
+Holds the clients of the deployments a service talks to by name. ex: "primary", "analytics", "archive"
+*/
+type Registry struct {
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+/*
+Creates an empty registry
+
+Returns:
+
+	*Registry pointer to a registry
+*/
+func NewRegistry() *Registry {
+	return &Registry{clients: map[string]*Client{}}
+}
+
+/*
+Creates a client for a deployment and adds it to the registry
+The client connects on first use
+
+	string: name of the deployment
+
+	string: username to authenticate with
+
+	string: password to authenticate with
+
+	string: url of db after the credentials. ex: @dbname.smchw.mongodb.net/test
+
+	...Option: options of the client
+
+Returns:
+
+	*Client pointer to the client
+
+	an err - error
+*/
+func (r *Registry) Add(name string, _username string, _password string, _url string, _options ...Option) (*Client, error) {
+	c := NewClient(_username, _password, _url, _options...)
+	if err := r.Register(name, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+/*
+Adds an existing client to the registry
+
+	string: name of the deployment
+
+	*Client client of the deployment
+
+Returns:
+
+	an err - error
+*/
+func (r *Registry) Register(name string, c *Client) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.clients[name]; ok {
+		return errors.New("a client named " + name + " is already registered")
+	}
+	r.clients[name] = c
+	return nil
+}
+
+/*
+Gets the client of a deployment
+
+	string: name of the deployment
+
+Returns:
+
+	*Client pointer to the client
+
+	an err - error
+*/
+func (r *Registry) Get(name string) (*Client, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.clients[name]
+	if !ok {
+		return nil, errors.New("no client named " + name + " is registered")
+	}
+	return c, nil
+}
+
+/*
+Names of the registered deployments, sorted
+
+Returns:
+
+	the names - []string
+*/
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/*
+State of the connection of every deployment, as last seen by the clients
+
+Returns:
+
+	the states by name - map[string]ConnState
+*/
+func (r *Registry) Status() map[string]ConnState {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	status := make(map[string]ConnState, len(r.clients))
+	for name, c := range r.clients {
+		status[name] = c.State()
+	}
+	return status
+}
+
+/*
+Pings every deployment
+
+Returns:
+
+	the error of each deployment, nil for the healthy ones - map[string]error
+*/
+func (r *Registry) Ping() map[string]error {
+	r.mu.RLock()
+	clients := make(map[string]*Client, len(r.clients))
+	for name, c := range r.clients {
+		clients[name] = c
+	}
+	r.mu.RUnlock()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(map[string]error, len(clients))
+	for name, c := range clients {
+		wg.Add(1)
+		go func(name string, c *Client) {
+			defer wg.Done()
+			err := c.ping()
+			mu.Lock()
+			errs[name] = err
+			mu.Unlock()
+		}(name, c)
+	}
+	wg.Wait()
+	return errs
+}
+
+/*
+Shuts every client down, see Client.Shutdown
+
+	context.Context context bounding the wait
+
+Returns:
+
+	an err - error, the first error of the clients
+*/
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	clients := r.clients
+	r.clients = map[string]*Client{}
+	r.mu.Unlock()
+
+	var first error
+	for _, c := range clients {
+		if err := c.Shutdown(ctx); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,80 @@
+package driver
+
+import "go.mongodb.org/mongo-driver/mongo"
+
+/*
+InsertResult object
+Result of an insert
+
+	InsertedID: _id of the first inserted object
+
+	InsertedIDs: _id of every inserted object, in the order of the objects
+*/
+type InsertResult struct {
+	InsertedID  interface{}
+	InsertedIDs []interface{}
+}
+
+/*
+UpdateResult object
+Result of an update or a replacement
+
+	Matched: number of documents matching the filter
+
+	Modified: number of documents that changed
+
+	Upserted: number of documents inserted by an upsert
+
+	UpsertedID: _id of the document inserted by an upsert, nil if none was
+*/
+type UpdateResult struct {
+	Matched    int64
+	Modified   int64
+	Upserted   int64
+	UpsertedID interface{}
+}
+
+/*
+DeleteResult object
+Result of a delete
+
+	Deleted: number of deleted documents
+*/
+type DeleteResult struct {
+	Deleted int64
+}
+
+func updateResult(res *mongo.UpdateResult) *UpdateResult {
+	return &UpdateResult{
+		Matched:    res.MatchedCount,
+		Modified:   res.ModifiedCount,
+		Upserted:   res.UpsertedCount,
+		UpsertedID: res.UpsertedID,
+	}
+}
+
+/*
+First _id of a list, nil if it is empty
+*/
+func firstID(ids []interface{}) interface{} {
+	if len(ids) == 0 {
+		return nil
+	}
+	return ids[0]
+}
+
+/*
+UpsertResult object
+Result of the upsert of one object by UpsertMany
+
+	Matched: whether a document with the same keys was replaced
+
+	UpsertedID: _id of the inserted document, nil if one was replaced
+
+	Err: error of the upsert of this object, nil if it went through
+*/
+type UpsertResult struct {
+	Matched    bool
+	UpsertedID interface{}
+	Err        error
+}
@@ -0,0 +1,122 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+Retries shared by a client and its copies, see WithRetryBudget
+*/
+type retryBudget struct {
+	mu     sync.Mutex
+	tokens float64
+	max    float64
+	ratio  float64
+}
+
+/*
+Sets how many times a failed operation is tried again by the client
+On top of the retryable reads and writes of the driver, writes are tried
+again once by default and reads are not. Override it for a single call,
+ex: c.With(WithRetries(0)).UpdateOne(filter, Update().Inc("balance", -10), nil) for an update that can't be applied twice
+
+	int: number of times to try again, 0 to never retry
+
+Returns:
+
+	an option - Option
+*/
+func WithRetries(retries int) Option {
+	return func(cf *config) {
+		if retries < 0 {
+			retries = 0
+		}
+		cf.retries = &retries
+	}
+}
+
+/*
+Limits the retries of a client and its copies so a flapping cluster doesn't get a retry storm
+The budget starts with max retries and earns back ratio of a retry for each
+operation that succeeds. Once it is spent operations fail on their first error.
+ex: WithRetryBudget(10, 0.1) allows a burst of 10 retries, then one every 10 successful operations
+
+	int: most retries the budget holds
+
+	float64: part of a retry earned by each successful operation
+
+Returns:
+
+	an option - Option
+*/
+func WithRetryBudget(max int, ratio float64) Option {
+	return func(cf *config) {
+		cf.budget = &retryBudget{tokens: float64(max), max: float64(max), ratio: ratio}
+	}
+}
+
+/*
+Tries an operation again while it fails, up to the retries of the client
+Errors that would fail again are returned as is: duplicate keys, rejected
+documents of bulk writes and expired contexts
+
+	int: retries of the operation when WithRetries is not set
+
+	error: error of the first attempt
+
+	func() error: attempt of the operation
+*/
+func (c *Client) retry(retries int, err error, again func() error) error {
+	if c.cf.retries != nil {
+		retries = *c.cf.retries
+	}
+	for i := 0; i < retries && retryable(err) && c.cf.budget.spend(); i++ {
+		err = again()
+	}
+	if err == nil {
+		c.cf.budget.earn()
+	}
+	return err
+}
+
+func retryable(err error) bool {
+	var bulk mongo.BulkWriteException
+	switch {
+	case err == nil, mongo.IsDuplicateKeyError(err), errors.As(err, &bulk):
+		return false
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return false
+	}
+	return true
+}
+
+/*
+Takes a retry from the budget, there is no limit without a budget
+*/
+func (b *retryBudget) spend() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *retryBudget) earn() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if b.tokens += b.ratio; b.tokens > b.max {
+		b.tokens = b.max
+	}
+	b.mu.Unlock()
+}
@@ -0,0 +1,278 @@
+package driver
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Saves an object into the collection
+If the object has an _id the document with that _id is replaced, or
+created if it doesn't exist. Otherwise the object is inserted and the
+generated ObjectID is set on the object's _id field when it is a pointer to a struct.
+Inserted objects get their defaults first, see Defaulter
+
+	interface{} object to save
+
+Returns:
+
+	the _id of the saved object - interface{}
+
+	an err - error
+*/
+func (c *Client) Save(object interface{}) (_ interface{}, err error) {
+	defer c.observe("save", time.Now(), &err)
+	if err := c.collection("saving an object"); err != nil {
+		return nil, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	if err := ValidateReplacement(object); err != nil {
+		return nil, err
+	}
+	doc, id, generated, err := withID(c.cf.codecRegistry(), object)
+	if err != nil {
+		return nil, err
+	}
+	if generated { // a new object, it may get an _id from its defaults
+		if object, err = c.defaults(object); err != nil {
+			return nil, err
+		}
+		if doc, id, generated, err = withID(c.cf.codecRegistry(), object); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.validate(object); err != nil {
+		return nil, err
+	}
+	// ping database
+	if err := c.ping(); err != nil {
+		return nil, err
+	}
+	if c.cf.dryRun != nil {
+		var filter interface{}
+		if !generated {
+			filter = bson.D{{Key: "_id", Value: id}}
+		}
+		if _, err := c.dryRun(ctx, "save", filter, doc, true); err != nil {
+			return nil, err
+		}
+		if generated {
+			setID(object, id)
+		}
+		return id, nil
+	}
+	if doc, err = c.fit(doc); err != nil {
+		return nil, err
+	}
+	if generated { // no _id was set
+		if _, err := c.co().InsertOne(ctx, doc); err != nil {
+			return nil, mapError(err)
+		}
+		setID(object, id)
+		return id, nil
+	}
+	_, err = c.co().ReplaceOne(ctx, bson.D{{Key: "_id", Value: id}}, doc, c.cf.replaceOptions(), options.Replace().SetUpsert(true))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return id, nil
+}
+
+/*
+Replaces the document matching the filter or inserts it if none matches
+
+	interface{} filter to query object by
+
+	interface{} object to replace the document with
+
+Returns:
+
+	the _id of the inserted document, nil if a document was replaced - interface{}
+
+	an err - error
+*/
+func (c *Client) Upsert(filter interface{}, object interface{}) (_ interface{}, err error) {
+	defer c.observe("upsert", time.Now(), &err)
+	if err := c.collection("upserting an object"); err != nil {
+		return nil, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	if err := ValidateReplacement(object); err != nil {
+		return nil, err
+	}
+	if err := c.validate(object); err != nil {
+		return nil, err
+	}
+	// ping database
+	if err := c.ping(); err != nil {
+		return nil, err
+	}
+	if c.cf.dryRun != nil { // the _id of an inserted document comes from the server
+		_, err := c.dryRun(ctx, "upsert", filter, object, true)
+		return nil, err
+	}
+	if object, err = c.fit(object); err != nil {
+		return nil, err
+	}
+	res, err := c.co().ReplaceOne(ctx, filter, object, c.cf.replaceOptions(), options.Replace().SetUpsert(true))
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return res.UpsertedID, nil
+}
+
+/*
+Replaces the documents with the same key fields as the objects, or inserts
+the objects no document matches, in one unordered bulk write
+The objects are written in any order and a failed object doesn't stop the others
+
+	[]interface{} objects to upsert
+
+	...string: fields identifying a document, _id when none are given. ex: "source", "externalId"
+
+Returns:
+
+	the result of each object, in the order of the objects - []UpsertResult
+
+	an err - error, the first failure when some objects failed
+*/
+func (c *Client) UpsertMany(objects []interface{}, keyFields ...string) (_ []UpsertResult, err error) {
+	defer c.observe("upsertMany", time.Now(), &err)
+	if err := c.collection("upserting objects"); err != nil {
+		return nil, err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	if len(keyFields) == 0 {
+		keyFields = []string{"_id"}
+	}
+	models := make([]mongo.WriteModel, len(objects))
+	filters := make([]bson.D, len(objects))
+	for i, object := range objects {
+		if err := ValidateReplacement(object); err != nil {
+			return nil, err
+		}
+		if err := c.validate(object); err != nil {
+			return nil, err
+		}
+		filter, err := keyFilter(c.cf.codecRegistry(), object, keyFields)
+		if err != nil {
+			return nil, err
+		}
+		filters[i] = filter
+		models[i] = mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(object).SetUpsert(true)
+	}
+	// ping database
+	if err := c.ping(); err != nil {
+		return nil, err
+	}
+	results := make([]UpsertResult, len(objects))
+	if len(objects) == 0 {
+		return results, nil
+	}
+	if c.cf.dryRun != nil {
+		for i := range objects {
+			matched, err := c.dryRun(ctx, "upsertMany", filters[i], objects[i], true)
+			if err != nil {
+				return nil, err
+			}
+			results[i].Matched = matched > 0
+		}
+		return results, nil
+	}
+	opts := options.BulkWrite().SetOrdered(false)
+	res, err := c.co().BulkWrite(ctx, models, opts)
+	var bulk mongo.BulkWriteException
+	err = c.retry(1, err, func() (err error) { // we try again, replacing twice is harmless
+		res, err = c.co().BulkWrite(ctx, models, opts)
+		return err
+	})
+	if err != nil && !errors.As(err, &bulk) {
+		return nil, mapError(err)
+	}
+	failed := map[int]error{}
+	for _, we := range bulk.WriteErrors {
+		failed[we.Index] = mapError(we)
+	}
+	for i := range results {
+		if e, ok := failed[i]; ok {
+			results[i].Err = e
+			continue
+		}
+		if id, ok := res.UpsertedIDs[int64(i)]; ok {
+			results[i].UpsertedID = id
+			continue
+		}
+		results[i].Matched = true
+	}
+	return results, mapError(err)
+}
+
+/*
+Filter matching the document with the same key fields as an object
+*/
+func keyFilter(reg *bsoncodec.Registry, object interface{}, keyFields []string) (bson.D, error) {
+	b, err := bson.MarshalWithRegistry(reg, object)
+	if err != nil {
+		return nil, err
+	}
+	filter := make(bson.D, 0, len(keyFields))
+	for _, field := range keyFields {
+		value, err := bson.Raw(b).LookupErr(strings.Split(field, ".")...)
+		if err != nil {
+			return nil, errors.New("object has no key field " + field)
+		}
+		filter = append(filter, bson.E{Key: field, Value: value})
+	}
+	return filter, nil
+}
+
+/*
+Sets the _id field of a struct
+Does nothing if the object is not a pointer to a struct or has no
+field that can hold the id
+
+	interface{} pointer to the struct
+
+	interface{} id to set, a primitive.ObjectID or its hex for string fields
+*/
+func setID(object interface{}, id interface{}) {
+	v := reflect.ValueOf(object)
+	if v.Kind() != reflect.Pointer {
+		return
+	}
+	field, ok := idField(v)
+	if !ok || !field.CanSet() {
+		return
+	}
+	value := reflect.ValueOf(id)
+	pointer := reflect.New(value.Type())
+	pointer.Elem().Set(value)
+	switch {
+	case value.Type().AssignableTo(field.Type()):
+		field.Set(value)
+	case pointer.Type().AssignableTo(field.Type()):
+		field.Set(pointer)
+	}
+}
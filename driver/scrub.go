@@ -0,0 +1,181 @@
+package driver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+/*
+ScrubAction is the transformation applied to a scrubbed field
+*/
+type ScrubAction string
+
+const (
+	ScrubHash ScrubAction = "hash" // keyed hash of the value, equal values stay equal so joins still work
+	ScrubMask ScrubAction = "mask" // strings keep their last 4 characters. ex: "************1234"
+	ScrubDrop ScrubAction = "drop" // the field is removed
+	ScrubFake ScrubAction = "fake" // a made up value of the same type, the same for equal values
+)
+
+/*
+ScrubRule object
+A field transformed when documents are scrubbed
+
+	Path: dotted path of the field, fields of arrays of documents are scrubbed in every element
+
+	Action: transformation of the field, ignored when Transform is set
+
+	Transform: custom transformation of the value of the field, can be nil
+*/
+type ScrubRule struct {
+	Path      string
+	Action    ScrubAction
+	Transform func(interface{}) interface{}
+}
+
+/*
+Scrubber object
+Anonymizes documents, ex: to copy production data to staging. See ExportOptions
+
+	Rules: fields to transform
+
+	Salt: secret key of the hashes and fakes, keep it out of the copy so values can't be guessed back
+*/
+type Scrubber struct {
+	Rules []ScrubRule
+	Salt  string
+}
+
+/*
+Scrubs a document
+
+	bson.Raw document to scrub
+
+Returns:
+
+	the scrubbed document - bson.Raw
+
+	an err - error
+*/
+func (s *Scrubber) Scrub(doc bson.Raw) (bson.Raw, error) {
+	if len(s.Rules) == 0 {
+		return doc, nil
+	}
+	var d bson.D
+	if err := bson.Unmarshal(doc, &d); err != nil {
+		return nil, err
+	}
+	for _, rule := range s.Rules {
+		rule := rule
+		d = scrubPath(d, strings.Split(rule.Path, "."), func(v interface{}) (interface{}, bool) {
+			return s.apply(rule, v)
+		})
+	}
+	return bson.Marshal(d)
+}
+
+/*
+Transforms a value with a rule, false when the field has to be removed
+*/
+func (s *Scrubber) apply(rule ScrubRule, v interface{}) (interface{}, bool) {
+	if rule.Transform != nil {
+		return rule.Transform(v), true
+	}
+	switch rule.Action {
+	case ScrubDrop:
+		return nil, false
+	case ScrubHash:
+		return s.hash(v), true
+	case ScrubMask:
+		str, ok := v.(string)
+		if !ok {
+			return nil, true
+		}
+		if len(str) <= 4 {
+			return strings.Repeat("*", len(str)), true
+		}
+		return strings.Repeat("*", len(str)-4) + str[len(str)-4:], true
+	case ScrubFake:
+		return s.fake(v), true
+	}
+	return v, true
+}
+
+func (s *Scrubber) hash(v interface{}) string {
+	mac := hmac.New(sha256.New, []byte(s.Salt))
+	fmt.Fprint(mac, v)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+/*
+Made up value of the same type as the value
+*/
+func (s *Scrubber) fake(v interface{}) interface{} {
+	h := s.hash(v)
+	switch v := v.(type) {
+	case string:
+		if strings.Contains(v, "@") {
+			return "user-" + h[:10] + "@example.com"
+		}
+		return "fake-" + h[:10]
+	case int32:
+		return int32(seed(h) % 1000)
+	case int64:
+		return int64(seed(h) % 1000)
+	case float64:
+		return float64(seed(h)%100000) / 100
+	case bool:
+		return seed(h)%2 == 0
+	case primitive.DateTime:
+		return primitive.NewDateTimeFromTime(time.Unix(int64(seed(h)%(50*365*24*3600)), 0))
+	}
+	return nil
+}
+
+func seed(h string) uint64 {
+	b, _ := hex.DecodeString(h[:16])
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n
+}
+
+func scrubPath(d bson.D, path []string, fn func(interface{}) (interface{}, bool)) bson.D {
+	for i := range d {
+		if d[i].Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			v, keep := fn(d[i].Value)
+			if !keep {
+				return append(d[:i:i], d[i+1:]...)
+			}
+			d[i].Value = v
+			return d
+		}
+		d[i].Value = scrubValue(d[i].Value, path[1:], fn)
+		return d
+	}
+	return d
+}
+
+func scrubValue(v interface{}, path []string, fn func(interface{}) (interface{}, bool)) interface{} {
+	switch v := v.(type) {
+	case bson.D:
+		return scrubPath(v, path, fn)
+	case bson.A:
+		for i := range v {
+			v[i] = scrubValue(v[i], path, fn)
+		}
+		return v
+	}
+	return v
+}
@@ -0,0 +1,115 @@
+package driver
+
+import (
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestScrub(t *testing.T) {
+	doc := bson.D{
+		{Key: "email", Value: "ada@lovelace.org"},
+		{Key: "card", Value: "4242424242424242"},
+		{Key: "pin", Value: "123"},
+		{Key: "password", Value: "secret"},
+		{Key: "age", Value: int32(36)},
+		{Key: "orders", Value: bson.A{
+			bson.D{{Key: "address", Value: "1 main st"}, {Key: "total", Value: int32(10)}},
+			bson.D{{Key: "address", Value: "2 main st"}, {Key: "total", Value: int32(20)}},
+		}},
+	}
+
+	tests := []struct {
+		name  string
+		rule  ScrubRule
+		check func(t *testing.T, d bson.M)
+	}{
+		{"mask keeps the last 4 characters", ScrubRule{Path: "card", Action: ScrubMask}, func(t *testing.T, d bson.M) {
+			if d["card"] != "************4242" {
+				t.Errorf("got %v", d["card"])
+			}
+		}},
+		{"mask hides short strings", ScrubRule{Path: "pin", Action: ScrubMask}, func(t *testing.T, d bson.M) {
+			if d["pin"] != "***" {
+				t.Errorf("got %v", d["pin"])
+			}
+		}},
+		{"drop", ScrubRule{Path: "password", Action: ScrubDrop}, func(t *testing.T, d bson.M) {
+			if _, ok := d["password"]; ok {
+				t.Error("password wasn't dropped")
+			}
+		}},
+		{"hash", ScrubRule{Path: "email", Action: ScrubHash}, func(t *testing.T, d bson.M) {
+			if s, _ := d["email"].(string); len(s) != 64 || strings.Contains(s, "ada") {
+				t.Errorf("got %v", d["email"])
+			}
+		}},
+		{"fake emails stay emails", ScrubRule{Path: "email", Action: ScrubFake}, func(t *testing.T, d bson.M) {
+			if s, _ := d["email"].(string); !strings.HasSuffix(s, "@example.com") {
+				t.Errorf("got %v", d["email"])
+			}
+		}},
+		{"fakes keep the type", ScrubRule{Path: "age", Action: ScrubFake}, func(t *testing.T, d bson.M) {
+			if _, ok := d["age"].(int32); !ok {
+				t.Errorf("got %T", d["age"])
+			}
+		}},
+		{"transform", ScrubRule{Path: "age", Transform: func(interface{}) interface{} { return "old" }}, func(t *testing.T, d bson.M) {
+			if d["age"] != "old" {
+				t.Errorf("got %v", d["age"])
+			}
+		}},
+		{"arrays of documents", ScrubRule{Path: "orders.address", Action: ScrubDrop}, func(t *testing.T, d bson.M) {
+			for _, order := range d["orders"].(bson.A) {
+				order := order.(bson.M)
+				if _, ok := order["address"]; ok {
+					t.Error("address wasn't dropped")
+				}
+				if _, ok := order["total"]; !ok {
+					t.Error("total was dropped")
+				}
+			}
+		}},
+		{"missing fields", ScrubRule{Path: "phone.number", Action: ScrubDrop}, func(t *testing.T, d bson.M) {
+			if len(d) != len(doc) {
+				t.Errorf("got %v", d)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Scrubber{Rules: []ScrubRule{tt.rule}, Salt: "salt"}
+			tt.check(t, scrub(t, s, doc))
+		})
+	}
+}
+
+func TestScrubIsDeterministic(t *testing.T) {
+	doc := bson.D{{Key: "email", Value: "ada@lovelace.org"}}
+	rules := []ScrubRule{{Path: "email", Action: ScrubHash}}
+	a := scrub(t, &Scrubber{Rules: rules, Salt: "a"}, doc)["email"]
+	if b := scrub(t, &Scrubber{Rules: rules, Salt: "a"}, doc)["email"]; a != b {
+		t.Errorf("equal values got different hashes %v and %v", a, b)
+	}
+	if b := scrub(t, &Scrubber{Rules: rules, Salt: "b"}, doc)["email"]; a == b {
+		t.Error("the hash doesn't depend on the salt")
+	}
+}
+
+func scrub(t *testing.T, s *Scrubber, doc bson.D) bson.M {
+	t.Helper()
+	b, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := s.Scrub(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m bson.M
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
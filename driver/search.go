@@ -0,0 +1,374 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+SearchOperator object
+An Atlas Search operator. ex: SearchText("coffee", "name", "description").Fuzzy(1)
+*/
+type SearchOperator struct {
+	name string
+	spec bson.D
+}
+
+/*
+SearchBuilder object
+Builds a $search stage. ex: SearchQuery(SearchAutocomplete("cof", "name")).Index("products").Highlight("name")
+*/
+type SearchBuilder struct {
+	index     string
+	op        *SearchOperator
+	highlight []string
+}
+
+/*
+Full text search on one or more fields
+
+	string: text to search for
+
+	...string: fields to search in
+*/
+func SearchText(query string, paths ...string) *SearchOperator {
+	return &SearchOperator{name: "text", spec: bson.D{
+		{Key: "query", Value: query},
+		{Key: "path", Value: searchPath(paths)},
+	}}
+}
+
+/*
+Search as you type on a field indexed with the autocomplete type
+
+	string: text typed so far
+
+	string: field to search in
+*/
+func SearchAutocomplete(query string, path string) *SearchOperator {
+	return &SearchOperator{name: "autocomplete", spec: bson.D{
+		{Key: "query", Value: query},
+		{Key: "path", Value: path},
+	}}
+}
+
+/*
+Combines operators with Must, Should, MustNot and Filter clauses
+*/
+func SearchCompound() *SearchOperator {
+	return &SearchOperator{name: "compound", spec: bson.D{}}
+}
+
+/*
+Matches documents with typos, up to maxEdits characters off (1 or 2)
+*/
+func (o *SearchOperator) Fuzzy(maxEdits int) *SearchOperator {
+	o.spec = append(o.spec, bson.E{Key: "fuzzy", Value: bson.D{{Key: "maxEdits", Value: maxEdits}}})
+	return o
+}
+
+/*
+Multiplies the score of matching documents
+*/
+func (o *SearchOperator) Boost(factor float64) *SearchOperator {
+	o.spec = append(o.spec, bson.E{Key: "score", Value: bson.D{{Key: "boost", Value: bson.D{{Key: "value", Value: factor}}}}})
+	return o
+}
+
+/*
+Compound clause that documents must match
+*/
+func (o *SearchOperator) Must(ops ...*SearchOperator) *SearchOperator {
+	return o.clause("must", ops)
+}
+
+/*
+Compound clause that documents should match, matches raise the score
+*/
+func (o *SearchOperator) Should(ops ...*SearchOperator) *SearchOperator {
+	return o.clause("should", ops)
+}
+
+/*
+Compound clause that documents must not match
+*/
+func (o *SearchOperator) MustNot(ops ...*SearchOperator) *SearchOperator {
+	return o.clause("mustNot", ops)
+}
+
+/*
+Compound clause that documents must match without changing the score
+*/
+func (o *SearchOperator) Filter(ops ...*SearchOperator) *SearchOperator {
+	return o.clause("filter", ops)
+}
+
+/*
+Number of Should clauses a document has to match
+*/
+func (o *SearchOperator) MinimumShouldMatch(n int) *SearchOperator {
+	o.spec = append(o.spec, bson.E{Key: "minimumShouldMatch", Value: n})
+	return o
+}
+
+func (o *SearchOperator) clause(name string, ops []*SearchOperator) *SearchOperator {
+	a := bson.A{}
+	for i := range o.spec {
+		if o.spec[i].Key == name {
+			a = o.spec[i].Value.(bson.A)
+			o.spec = append(o.spec[:i], o.spec[i+1:]...)
+			break
+		}
+	}
+	for _, op := range ops {
+		a = append(a, op.D())
+	}
+	o.spec = append(o.spec, bson.E{Key: name, Value: a})
+	return o
+}
+
+/*
+Returns the operator document
+
+Returns:
+
+	a document - bson.D
+*/
+func (o *SearchOperator) D() bson.D {
+	return bson.D{{Key: o.name, Value: o.spec}}
+}
+
+/*
+Creates a new $search stage
+
+	*SearchOperator operator to search with
+
+Returns:
+
+	*SearchBuilder pointer to a search builder
+*/
+func SearchQuery(op *SearchOperator) *SearchBuilder {
+	return &SearchBuilder{op: op}
+}
+
+/*
+Sets the search index to use, "default" when not set
+*/
+func (s *SearchBuilder) Index(name string) *SearchBuilder {
+	s.index = name
+	return s
+}
+
+/*
+Returns the matching snippets of the fields, project them with ProjectionBuilder.SearchHighlights
+*/
+func (s *SearchBuilder) Highlight(paths ...string) *SearchBuilder {
+	s.highlight = paths
+	return s
+}
+
+/*
+Returns the $search stage
+
+Returns:
+
+	a document - bson.D
+*/
+func (s *SearchBuilder) D() bson.D {
+	spec := bson.D{}
+	if s.index != "" {
+		spec = append(spec, bson.E{Key: "index", Value: s.index})
+	}
+	spec = append(spec, s.op.D()...)
+	if len(s.highlight) > 0 {
+		spec = append(spec, bson.E{Key: "highlight", Value: bson.D{{Key: "path", Value: searchPath(s.highlight)}}})
+	}
+	return bson.D{{Key: "$search", Value: spec}}
+}
+
+/*
+Adds a $search stage to the pipeline, it must be the first stage
+*/
+func (p *PipelineBuilder) Search(search *SearchBuilder) *PipelineBuilder {
+	p.p = append(p.p, search.D())
+	return p
+}
+
+/*
+Projects the search score into a field
+*/
+func (p *ProjectionBuilder) SearchScore(field string) *ProjectionBuilder {
+	p.d = append(p.d, bson.E{Key: field, Value: bson.D{{Key: "$meta", Value: "searchScore"}}})
+	return p
+}
+
+/*
+Projects the search highlights into a field
+*/
+func (p *ProjectionBuilder) SearchHighlights(field string) *ProjectionBuilder {
+	p.d = append(p.d, bson.E{Key: field, Value: bson.D{{Key: "$meta", Value: "searchHighlights"}}})
+	return p
+}
+
+/*
+Runs an Atlas Search query on the collection and decodes the results
+
+	*SearchBuilder search to run
+
+	int64 maximum number of results, 0 for no limit
+
+	interface{} pointer to a slice to decode the results into
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) AtlasSearch(search *SearchBuilder, limit int64, results interface{}) (err error) {
+	defer c.observe("atlasSearch", time.Now(), &err)
+	if err := c.collection("searching"); err != nil {
+		return err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	p := Pipeline().Search(search)
+	if limit > 0 {
+		p.Limit(limit)
+	}
+	// ping database
+	if err := c.ping(); err != nil {
+		return err
+	}
+	cursor, err := c.co().Aggregate(ctx, p.Stages(), c.cf.aggregateOptions())
+	if err != nil {
+		return mapError(err)
+	}
+	return c.all(ctx, cursor, results)
+}
+
+/*
+Creates an Atlas Search index on the collection
+
+	string: name of the index
+
+	interface{} index definition. ex: bson.D{{Key: "mappings", Value: bson.D{{Key: "dynamic", Value: true}}}}
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) CreateSearchIndex(name string, definition interface{}) (err error) {
+	defer c.observe("createSearchIndex", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if err := c.collection("creating a search index"); err != nil {
+		return err
+	}
+	cmd := bson.D{
+		{Key: "createSearchIndexes", Value: c.co().Name()},
+		{Key: "indexes", Value: bson.A{bson.D{{Key: "name", Value: name}, {Key: "definition", Value: definition}}}},
+	}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("createSearchIndex", cmd)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	return mapError(c.db().RunCommand(ctx, cmd).Err())
+}
+
+/*
+Updates the definition of an Atlas Search index
+
+	string: name of the index
+
+	interface{} new index definition
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) UpdateSearchIndex(name string, definition interface{}) (err error) {
+	defer c.observe("updateSearchIndex", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if err := c.collection("updating a search index"); err != nil {
+		return err
+	}
+	cmd := bson.D{
+		{Key: "updateSearchIndex", Value: c.co().Name()},
+		{Key: "name", Value: name},
+		{Key: "definition", Value: definition},
+	}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("updateSearchIndex", cmd)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	return mapError(c.db().RunCommand(ctx, cmd).Err())
+}
+
+/*
+Drops an Atlas Search index
+
+	string: name of the index
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) DropSearchIndex(name string) (err error) {
+	defer c.observe("dropSearchIndex", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if err := c.collection("dropping a search index"); err != nil {
+		return err
+	}
+	cmd := bson.D{
+		{Key: "dropSearchIndex", Value: c.co().Name()},
+		{Key: "name", Value: name},
+	}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("dropSearchIndex", cmd)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	return mapError(c.db().RunCommand(ctx, cmd).Err())
+}
+
+/*
+Lists the Atlas Search indexes of the collection
+
+Returns:
+
+	the indexes - []bson.M
+
+	an err - error
+*/
+func (c *Client) ListSearchIndexes() (_ []bson.M, err error) {
+	defer c.observe("listSearchIndexes", time.Now(), &err)
+	if err := c.collection("listing search indexes"); err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	cursor, err := c.co().Aggregate(ctx, Pipeline().Stage("$listSearchIndexes", bson.D{}).Stages())
+	if err != nil {
+		return nil, mapError(err)
+	}
+	var indexes []bson.M
+	err = cursor.All(ctx, &indexes)
+	return indexes, mapError(err)
+}
+
+/*
+A single path or a list of paths
+*/
+func searchPath(paths []string) interface{} {
+	if len(paths) == 1 {
+		return paths[0]
+	}
+	return paths
+}
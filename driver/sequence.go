@@ -0,0 +1,87 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Collection holding the sequences when WithSequences isn't used
+*/
+const DefaultSequences = "counters"
+
+/*
+Keeps the sequences of NextSequence in a collection of the database
+
+	string: name of the collection. ex: counters
+
+Returns:
+
+	an option - Option
+*/
+func WithSequences(collection string) Option {
+	return func(cf *config) {
+		cf.sequences = collection
+	}
+}
+
+/*
+Returns the next number of a sequence, ex: for invoice or ticket numbers
+Sequences start at 1 and are kept in the counters collection of the database,
+one document per sequence. The increment is atomic so every instance of a
+service gets a different number, a failed write may leave a gap
+
+	string: name of the sequence. ex: invoices
+
+Returns:
+
+	the number - int64
+
+	an err - error, ErrDryRun in dry-run mode
+*/
+func (c *Client) NextSequence(name string) (_ int64, err error) {
+	defer c.observe("nextSequence", time.Now(), &err)
+	if c.db() == nil {
+		return 0, noDatabase("getting a sequence")
+	}
+	if err := c.writable(); err != nil {
+		return 0, err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return 0, err
+	}
+	collection := c.cf.sequences
+	if collection == "" {
+		collection = DefaultSequences
+	}
+	co := c.db().Collection(collection)
+	filter := bson.D{{Key: "_id", Value: name}}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "seq", Value: int64(1)}}}}
+	if c.cf.dryRun != nil { // the number comes from the write
+		c.cf.dryRun(DryRunWrite{Operation: "nextSequence", Filter: filter, Document: update})
+		return 0, ErrDryRun
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	next := func() (int64, error) {
+		var counter struct {
+			Seq int64 `bson:"seq"`
+		}
+		err := co.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+		if mongo.IsDuplicateKeyError(err) { // another instance created the sequence first
+			err = co.FindOneAndUpdate(ctx, filter, update, opts).Decode(&counter)
+		}
+		return counter.Seq, err
+	}
+	seq, err := next()
+	err = c.retry(1, err, func() (err error) { // we try again
+		seq, err = next()
+		return err
+	})
+	return seq, mapError(err)
+}
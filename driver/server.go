@@ -0,0 +1,150 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+ServerStatus object
+Contains the most used serverStatus metrics, Raw holds the complete output
+*/
+type ServerStatus struct {
+	Host        string  `bson:"host" json:"host"`
+	Version     string  `bson:"version" json:"version"`
+	Process     string  `bson:"process" json:"process"`
+	Uptime      float64 `bson:"uptime" json:"uptime"`
+	Connections struct {
+		Current      int64 `bson:"current" json:"current"`
+		Available    int64 `bson:"available" json:"available"`
+		TotalCreated int64 `bson:"totalCreated" json:"totalCreated"`
+	} `bson:"connections" json:"connections"`
+	Opcounters struct {
+		Insert  int64 `bson:"insert" json:"insert"`
+		Query   int64 `bson:"query" json:"query"`
+		Update  int64 `bson:"update" json:"update"`
+		Delete  int64 `bson:"delete" json:"delete"`
+		GetMore int64 `bson:"getmore" json:"getmore"`
+		Command int64 `bson:"command" json:"command"`
+	} `bson:"opcounters" json:"opcounters"`
+	Mem struct {
+		Resident int64 `bson:"resident" json:"resident"`
+		Virtual  int64 `bson:"virtual" json:"virtual"`
+	} `bson:"mem" json:"mem"`
+	Raw bson.Raw `bson:"-" json:"-"`
+}
+
+/*
+Operation object
+Contains an operation running on the server, Raw holds the complete $currentOp entry
+*/
+type Operation struct {
+	OpID        interface{} `bson:"opid" json:"opid"`
+	Type        string      `bson:"type" json:"type"`
+	Op          string      `bson:"op" json:"op"`
+	Namespace   string      `bson:"ns" json:"ns"`
+	Active      bool        `bson:"active" json:"active"`
+	SecsRunning int64       `bson:"secs_running" json:"secs_running"`
+	Client      string      `bson:"client" json:"client"`
+	AppName     string      `bson:"appName" json:"appName"`
+	Command     bson.Raw    `bson:"command" json:"command"`
+	PlanSummary string      `bson:"planSummary" json:"planSummary"`
+	Raw         bson.Raw    `bson:"-" json:"-"`
+}
+
+/*
+Returns the status of the server the client is connected to
+
+Returns:
+
+	the status - *ServerStatus
+
+	an err - error
+*/
+func (c *Client) ServerStatus() (*ServerStatus, error) {
+	var raw bson.Raw
+	if err := c.RunCommand("admin", bson.D{{Key: "serverStatus", Value: 1}}, &raw); err != nil {
+		return nil, err
+	}
+	var status ServerStatus
+	if err := bson.Unmarshal(raw, &status); err != nil {
+		return nil, err
+	}
+	status.Raw = raw
+	return &status, nil
+}
+
+/*
+Lists the operations running on the server
+
+	interface{} filter on the $currentOp entries, nil for every operation. ex: F("ns").Eq("app.users")
+
+Returns:
+
+	the operations - []Operation
+
+	an err - error
+*/
+func (c *Client) CurrentOps(filter interface{}) (_ []Operation, err error) {
+	defer c.observe("currentOps", time.Now(), &err)
+	if c.connected() != nil {
+		return nil, notConnected("listing operations")
+	}
+	p := Pipeline().Stage("$currentOp", bson.D{{Key: "allUsers", Value: true}})
+	if filter != nil {
+		p.Match(filter)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	cursor, err := c.cl().Database("admin").Aggregate(ctx, p.Stages())
+	if err != nil {
+		return nil, mapError(err)
+	}
+	defer cursor.Close(ctx)
+	var operations []Operation
+	for cursor.Next(ctx) {
+		var op Operation
+		if err := cursor.Decode(&op); err != nil {
+			return nil, err
+		}
+		op.Raw = append(bson.Raw(nil), cursor.Current...)
+		operations = append(operations, op)
+	}
+	return operations, mapError(cursor.Err())
+}
+
+/*
+Lists the active operations that have been running for longer than a duration
+
+	time.Duration minimum running time
+
+Returns:
+
+	the operations - []Operation
+
+	an err - error
+*/
+func (c *Client) LongRunningOps(longer time.Duration) ([]Operation, error) {
+	return c.CurrentOps(F("active").Eq(true).And(F("secs_running").Gte(int64(longer / time.Second))))
+}
+
+/*
+Kills an operation running on the server
+
+	interface{} opid of the operation, from Operation.OpID
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) KillOp(opid interface{}) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	cmd := bson.D{{Key: "killOp", Value: 1}, {Key: "op", Value: opid}}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("killOp", cmd)
+	}
+	return c.RunCommand("admin", cmd, nil)
+}
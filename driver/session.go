@@ -0,0 +1,293 @@
+package driver
+
+import (
+	"context"
+	"encoding/base64"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Session object
+Operations run through a session are causally consistent, a read
+will always see the writes made before it in the same session
+*/
+type Session struct {
+	se mongo.Session
+	c  *Client
+}
+
+/*
+SessionToken object
+Contains the cluster and operation time of a session so that
+another service can continue reading from the same point in time
+*/
+type SessionToken struct {
+	ClusterTime   bson.Raw            `bson:"clusterTime"`
+	OperationTime primitive.Timestamp `bson:"operationTime"`
+}
+
+/*
+Key of the session carried by a context
+*/
+type sessionKey struct{}
+
+/*
+Starts a new causally consistent session
+
+Returns:
+
+	*Session pointer to a session object
+
+	an err - error
+*/
+func (c *Client) StartSession() (*Session, error) {
+	return c.startSession(options.Session().SetCausalConsistency(true))
+}
+
+/*
+Starts a new snapshot session
+Every read made through the session sees the data as it was at a single
+point in time across all collections. Snapshot sessions can only be used for reads
+
+Returns:
+
+	*Session pointer to a session object
+
+	an err - error
+*/
+func (c *Client) StartSnapshotSession() (*Session, error) {
+	return c.startSession(options.Session().SetSnapshot(true))
+}
+
+/*
+Runs a set of reads at a single snapshot
+The client passed to the function is bound to a snapshot session that
+is ended once the function returns. ex: for multi-collection reports
+
+	func(*Client) error: function running the reads
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) Snapshot(reads func(*Client) error) error {
+	s, err := c.StartSnapshotSession()
+	if err != nil {
+		return err
+	}
+	defer s.End()
+	return reads(s.Client())
+}
+
+/*
+Runs a set of operations in a transaction
+The client passed to the function is bound to the transaction, which is
+committed when the function returns nil and aborted otherwise. The function
+may be called again when the transaction hits a transient error
+
+	func(*Client) error: function running the operations
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) Transaction(operations func(*Client) error) error {
+	// join the transaction the client is already bound to, it is committed by its owner
+	if inTransaction(c.context()) {
+		return operations(c)
+	}
+	s, err := c.StartSession()
+	if err != nil {
+		return err
+	}
+	defer s.End()
+	_, err = s.se.WithTransaction(c.context(), func(sc mongo.SessionContext) (interface{}, error) {
+		client := c.copy()
+		client.cx = ContextWithSession(sc, s)
+		return nil, operations(client)
+	})
+	return err
+}
+
+func (c *Client) startSession(opts *options.SessionOptions) (*Session, error) {
+	if c.connected() != nil {
+		return nil, notConnected("starting a session")
+	}
+	se, err := c.cl().StartSession(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{se: se, c: c}, nil
+}
+
+/*
+Returns a copy of the client bound to the session
+Every operation made with the returned client runs inside the session
+
+Returns:
+
+	*Client pointer to a client object
+*/
+func (s *Session) Client() *Client {
+	client := s.c.copy()
+	client.cx = ContextWithSession(context.Background(), s)
+	return client
+}
+
+/*
+Returns a context carrying the session
+Clients bound to the context with WithContext run their operations inside the session
+
+	context.Context: parent context
+
+	*Session session to carry
+
+Returns:
+
+	a context - context.Context
+*/
+func ContextWithSession(ctx context.Context, s *Session) context.Context {
+	return context.WithValue(mongo.NewSessionContext(ctx, s.se), sessionKey{}, s)
+}
+
+/*
+Returns the session carried by a context, ex: the context of a client passed to Transaction
+Repositories can pass the context around instead of the client bound to the session
+
+	context.Context: context to get the session of
+
+Returns:
+
+	*Session pointer to a session object, nil when the context has no session
+*/
+func SessionFromContext(ctx context.Context) *Session {
+	if ctx == nil {
+		return nil
+	}
+	s, _ := ctx.Value(sessionKey{}).(*Session)
+	return s
+}
+
+/*
+Returns a copy of the client running its operations with a context
+Operations join the session or transaction carried by the context, if any,
+and stop when it is cancelled. ex: c.WithContext(ctx).InsertOne(obj, nil)
+
+	context.Context: context to run the operations with
+
+Returns:
+
+	*Client pointer to a client object
+*/
+func (c *Client) WithContext(ctx context.Context) *Client {
+	client := c.copy()
+	client.cx = ctx
+	return client
+}
+
+/*
+Returns the context the operations of the client run with
+Inside Transaction it carries the transaction, see SessionFromContext
+
+Returns:
+
+	a context - context.Context
+*/
+func (c *Client) Context() context.Context {
+	return c.context()
+}
+
+/*
+Checks if a context carries a session with a transaction in progress
+*/
+func inTransaction(ctx context.Context) bool {
+	se, ok := mongo.SessionFromContext(ctx).(mongo.XSession)
+	return ok && se.ClientSession().TransactionRunning()
+}
+
+/*
+Ends the session
+The session and the clients bound to it should not be used afterwards
+*/
+func (s *Session) End() {
+	s.se.EndSession(context.Background())
+}
+
+/*
+Token of the latest cluster and operation time seen by the session
+
+Returns:
+
+	a token - SessionToken
+*/
+func (s *Session) Token() SessionToken {
+	token := SessionToken{ClusterTime: s.se.ClusterTime()}
+	if ot := s.se.OperationTime(); ot != nil {
+		token.OperationTime = *ot
+	}
+	return token
+}
+
+/*
+Advances the session to a token received from another session
+Reads made afterwards will see every write the other session has seen
+
+	SessionToken: token to advance to
+
+Returns:
+
+	an err - error
+*/
+func (s *Session) Advance(token SessionToken) error {
+	if token.ClusterTime != nil {
+		if err := s.se.AdvanceClusterTime(token.ClusterTime); err != nil {
+			return err
+		}
+	}
+	if !token.OperationTime.IsZero() {
+		return s.se.AdvanceOperationTime(&token.OperationTime)
+	}
+	return nil
+}
+
+/*
+Encodes the token into a string that can be sent in a header
+
+Returns:
+
+	an encoded token - string
+
+	an err - error
+*/
+func (t SessionToken) Encode() (string, error) {
+	b, err := bson.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+/*
+Decodes a token created with SessionToken.Encode
+
+	string: encoded token
+
+Returns:
+
+	a token - SessionToken
+
+	an err - error
+*/
+func DecodeSessionToken(encoded string) (SessionToken, error) {
+	var token SessionToken
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return token, err
+	}
+	err = bson.Unmarshal(b, &token)
+	return token, err
+}
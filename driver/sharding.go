@@ -0,0 +1,98 @@
+package driver
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+ShardOptions object
+Optional settings of ShardCollection
+
+	Unique: enforce a unique index on the shard key
+
+	NumInitialChunks: chunks to create when hashed sharding an empty collection, 0 for the server default
+
+	PresplitHashedZones: create the chunks of the zones of a compound hashed key when the collection is empty
+*/
+type ShardOptions struct {
+	Unique              bool
+	NumInitialChunks    int
+	PresplitHashedZones bool
+}
+
+/*
+Enables sharding for a database
+Only needed before MongoDB 6.0, later versions shard any database
+
+	string: name of the database
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) EnableSharding(db string) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	cmd := bson.D{{Key: "enableSharding", Value: db}}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("enableSharding", cmd)
+	}
+	return c.RunCommand("admin", cmd, nil)
+}
+
+/*
+Shards a collection on a key
+
+	string: namespace of the collection. ex: "app.users"
+
+	interface{} shard key, must be ordered. ex: bson.D{{Key: "userId", Value: "hashed"}}
+
+	ShardOptions optional settings, the zero value for the defaults
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) ShardCollection(ns string, key interface{}, opts ShardOptions) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	cmd := bson.D{
+		{Key: "shardCollection", Value: ns},
+		{Key: "key", Value: key},
+	}
+	if opts.Unique {
+		cmd = append(cmd, bson.E{Key: "unique", Value: true})
+	}
+	if opts.NumInitialChunks > 0 {
+		cmd = append(cmd, bson.E{Key: "numInitialChunks", Value: opts.NumInitialChunks})
+	}
+	if opts.PresplitHashedZones {
+		cmd = append(cmd, bson.E{Key: "presplitHashedZones", Value: true})
+	}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("shardCollection", cmd)
+	}
+	return c.RunCommand("admin", cmd, nil)
+}
+
+/*
+Checks if the client is connected to a sharded cluster through mongos
+
+Returns:
+
+	a boolean - bool
+
+	an err - error
+*/
+func (c *Client) IsSharded() (bool, error) {
+	var hello struct {
+		Msg string `bson:"msg"`
+	}
+	if err := c.RunCommand("admin", bson.D{{Key: "hello", Value: 1}}, &hello); err != nil {
+		return false, err
+	}
+	// mongos always answers hello with this message
+	return hello.Msg == "isdbgrid", nil
+}
@@ -0,0 +1,104 @@
+package driver
+
+import (
+	"context"
+	"time"
+)
+
+/*
+Stops the client gracefully
+New operations fail with ErrShutdown, batch writers are flushed, subscriptions
+are stopped and the operations in flight are waited for until the context is
+done, then the client disconnects. Every client created with With is stopped too
+
+	context.Context context bounding the wait
+
+Returns:
+
+	an err - error, the error of the context if it was done before the operations finished
+*/
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.cn.mu.Lock()
+	c.cn.draining = true
+	closers := c.cn.closers
+	c.cn.closers = nil
+	c.cn.mu.Unlock()
+
+	var first error
+	for _, closer := range closers {
+		if err := closer(); err != nil && first == nil {
+			first = err
+		}
+	}
+	if err := c.cn.drain(ctx); err != nil && first == nil {
+		first = err
+	}
+	if _, err := c.Disconnect(); err != nil && first == nil {
+		first = err
+	}
+	return first
+}
+
+/*
+Counts an operation in flight
+*/
+func (cn *connection) begin() {
+	cn.mu.Lock()
+	cn.inflight++
+	cn.mu.Unlock()
+}
+
+func (cn *connection) end() {
+	cn.mu.Lock()
+	cn.inflight--
+	cn.mu.Unlock()
+}
+
+/*
+Checks if the client is shutting down
+*/
+func (cn *connection) closing() bool {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+	return cn.draining
+}
+
+/*
+Waits for the operations in flight to finish
+*/
+func (cn *connection) drain(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		cn.mu.Lock()
+		n := cn.inflight
+		cn.mu.Unlock()
+		if n == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+Registers a function stopping a background worker on shutdown
+The key removes it once the worker is stopped by its owner
+*/
+func (cn *connection) onShutdown(key interface{}, closer func() error) {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+	if cn.closers == nil {
+		cn.closers = map[interface{}]func() error{}
+	}
+	cn.closers[key] = closer
+}
+
+func (cn *connection) forget(key interface{}) {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+	delete(cn.closers, key)
+}
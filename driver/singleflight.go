@@ -0,0 +1,126 @@
+package driver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+Calls in flight of a client and its copies
+*/
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+/*
+Coalesces the identical FindOne, FindByID and Count calls made at the same time
+Calls with the same filter and read options on the same collection share the round
+trip of the first one, and its error when it fails or its context is cancelled.
+Calls made inside a session or a transaction are never shared. Use it with
+WithCache to absorb cache stampedes
+
+Returns:
+
+	an option - Option
+*/
+func WithSingleflight() Option {
+	return func(cf *config) {
+		cf.flight = &flightGroup{calls: map[string]*flightCall{}}
+	}
+}
+
+/*
+Runs the function once for all the callers of the same key
+*/
+func (g *flightGroup) do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.value, call.err = fn()
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(call.done)
+	return call.value, call.err
+}
+
+/*
+Key of a call, made of the operation, the collection, a hash of the filter and the read options
+*/
+func (c *Client) flightKey(op string, filter interface{}) (string, error) {
+	b, err := bson.MarshalWithRegistry(c.cf.codecRegistry(), filter)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return op + ":" + c.namespace() + ":" + hex.EncodeToString(sum[:]) + c.cf.readKey(), nil
+}
+
+/*
+Finds one object, sharing the call with the identical ones in flight
+*/
+func (c *Client) findOne(ctx context.Context, filter interface{}) *mongo.SingleResult {
+	// calls in a session can't share a result read outside of it
+	if c.cf.flight == nil || mongo.SessionFromContext(ctx) != nil {
+		return c.findOneCached(ctx, filter)
+	}
+	key, err := c.flightKey("findOne", filter)
+	if err != nil {
+		return errorResult(err)
+	}
+	raw, err := c.cf.flight.do(key, func() (interface{}, error) {
+		return c.findOneCached(ctx, filter).DecodeBytes()
+	})
+	if err != nil {
+		return errorResult(err)
+	}
+	return mongo.NewSingleResultFromDocument(raw.(bson.Raw), nil, nil)
+}
+
+/*
+Counts the objects matching a filter, sharing the call with the identical ones in flight
+*/
+func (c *Client) count(ctx context.Context, filter interface{}) (int64, error) {
+	count := func() (n int64, err error) {
+		n, err = c.co().CountDocuments(ctx, filter, c.cf.countOptions())
+		err = c.retry(0, err, func() error {
+			n, err = c.co().CountDocuments(ctx, filter, c.cf.countOptions())
+			return err
+		})
+		return n, err
+	}
+	if c.cf.flight == nil || mongo.SessionFromContext(ctx) != nil {
+		n, err := count()
+		return n, mapError(err)
+	}
+	key, err := c.flightKey("count", filter)
+	if err != nil {
+		return 0, err
+	}
+	n, err := c.cf.flight.do(key, func() (interface{}, error) {
+		return count()
+	})
+	if err != nil {
+		return 0, mapError(err)
+	}
+	return n.(int64), nil
+}
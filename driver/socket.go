@@ -0,0 +1,57 @@
+package driver
+
+import (
+	"net/url"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Connects directly to a single server with a mongodb:// url instead of
+discovering the cluster through its SRV record
+The url of the client is then the host after the credentials. ex: @localhost:27017
+
+Returns:
+
+	an option - Option
+*/
+func WithDirectConnection() Option {
+	return func(cf *config) {
+		cf.direct = true
+	}
+}
+
+/*
+Connects to a local server through a Unix domain socket, ex: a sidecar
+The url of the client is ignored and the connection is direct
+
+	string: path of the socket, it must end with .sock. ex: /tmp/mongodb-27017.sock
+
+Returns:
+
+	an option - Option
+*/
+func WithUnixSocket(path string) Option {
+	return func(cf *config) {
+		cf.socket = path
+	}
+}
+
+/*
+Client options pointing to the server, with the credentials of the client
+*/
+func (c *Client) clientURI() *options.ClientOptions {
+	if c.cf.socket != "" {
+		opts := options.Client().SetHosts([]string{c.cf.socket}).SetDirect(true)
+		if c.cr.username != "" {
+			opts.SetAuth(options.Credential{Username: c.cr.username, Password: c.cr.password})
+		}
+		return opts
+	}
+	// escape the credentials, generated passwords often hold characters like @ : or /
+	credentials := url.UserPassword(c.cr.username, c.cr.password).String()
+	if c.cf.direct {
+		return options.Client().ApplyURI(`mongodb://` + credentials + c.u).SetDirect(true)
+	}
+	return options.Client().ApplyURI(`mongodb+srv://` + credentials + c.u)
+}
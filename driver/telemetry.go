@@ -0,0 +1,94 @@
+package driver
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+ErrorEvent object
+An operation of the client that failed
+
+	Operation: name of the operation. ex: "updateOne"
+
+	Database: database of the client, empty when none is set
+
+	Collection: collection of the client, empty when none is set
+
+	Duration: time the operation ran for, retries included
+
+	Kind: error of the client the failure matches. ex: ErrTimeout, nil when it matches none
+
+	Err: error returned to the caller
+*/
+type ErrorEvent struct {
+	Operation  string
+	Database   string
+	Collection string
+	Duration   time.Duration
+	Kind       error
+	Err        error
+}
+
+/*
+Errors of the client an ErrorEvent can be categorized as, most specific first
+*/
+var errorKinds = []error{ErrDuplicateKey, ErrTimeout, ErrNotConnected, ErrNoCollectionSet, ErrNoDatabaseSet, ErrShutdown, ErrReadOnly, ErrDocumentTooLarge, ErrInvalidDocument, ErrDryRun}
+
+/*
+Registers a callback called for every failed operation, ex: to report errors to Sentry
+Finding nothing is not a failure so ErrNotFound is not reported.
+Callbacks run on the goroutine of the operation and should return quickly
+
+	func(ErrorEvent): callback receiving the failures
+*/
+func (c *Client) OnError(callback func(ErrorEvent)) {
+	c.cn.mu.Lock()
+	defer c.cn.mu.Unlock()
+	c.cn.failures = append(c.cn.failures, callback)
+}
+
+/*
+Reports the error of an operation to the callbacks, deferred at the start of the operation
+*/
+func (c *Client) observe(op string, start time.Time, err *error) {
+	mapped := mapError(*err)
+	if mapped == nil || errors.Is(mapped, ErrNotFound) {
+		return
+	}
+	c.cn.mu.Lock()
+	callbacks := c.cn.failures
+	c.cn.mu.Unlock()
+	if len(callbacks) == 0 {
+		return
+	}
+	e := ErrorEvent{Operation: op, Duration: time.Since(start), Err: *err}
+	if c.db() != nil {
+		e.Database = c.db().Name()
+	}
+	if c.co() != nil {
+		e.Collection = c.co().Name()
+	}
+	for _, kind := range errorKinds {
+		if errors.Is(mapped, kind) {
+			e.Kind = kind
+			break
+		}
+	}
+	for _, callback := range callbacks {
+		callback(e)
+	}
+}
+
+/*
+Reports the error of an operation returning a single result
+*/
+func (c *Client) observeResult(op string, start time.Time, res **mongo.SingleResult) {
+	if *res == nil {
+		return
+	}
+	err := (*res).Err()
+	c.observe(op, start, &err)
+}
@@ -0,0 +1,135 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+Longest term accepted by Search
+*/
+const maxSearchTerm = 256
+
+/*
+Escapes every regex metacharacter of a string so it matches literally
+Always escape user input before putting it in a $regex
+
+	string: text to escape
+
+Returns:
+
+	the escaped text - string
+*/
+func EscapeRegex(s string) string {
+	return regexp.QuoteMeta(s)
+}
+
+/*
+Matches documents containing the words of the search in a text indexed field
+*/
+func Text(search string) *Filter {
+	return &Filter{d: bson.D{{Key: "$text", Value: bson.D{{Key: "$search", Value: search}}}}}
+}
+
+/*
+Field starts with the text, which is escaped so it matches literally
+
+	string: text the field starts with
+
+	bool: ignore the case, the index can't be used as efficiently when set
+*/
+func (f *Filter) StartsWith(text string, ignoreCase bool) *Filter {
+	opts := ""
+	if ignoreCase {
+		opts = "i"
+	}
+	return f.Regex("^"+EscapeRegex(text), opts)
+}
+
+/*
+Searches a field for a term and decodes the matching objects into results
+Uses $text sorted by relevance when the field is part of a text index,
+otherwise matches the field prefix with an escaped, anchored $regex
+
+	string: field to search in
+
+	string: term to search for
+
+	interface{} pointer to a slice to decode the objects into
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) Search(field string, term string, results interface{}) (err error) {
+	defer c.observe("search", time.Now(), &err)
+	if err := c.collection("searching"); err != nil {
+		return err
+	}
+	if term == "" {
+		return errors.New("search term is empty")
+	}
+	if len(term) > maxSearchTerm {
+		return errors.New("search term is too long")
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return err
+	}
+
+	indexed, err := c.textIndexed(ctx, field)
+	if err != nil {
+		return mapError(err)
+	}
+	var filter *Filter
+	opts := c.cf.findOptions()
+	if indexed {
+		filter = Text(term)
+		opts.SetProjection(Project().TextScore("score")).SetSort(Sort().TextScore("score"))
+	} else {
+		filter = F(field).StartsWith(term, true)
+	}
+	cursor, err := c.co().Find(ctx, filter, opts)
+	if err != nil {
+		return mapError(err)
+	}
+	return c.all(ctx, cursor, results)
+}
+
+/*
+Checks if a field is part of a text index of the collection
+*/
+func (c *Client) textIndexed(ctx context.Context, field string) (bool, error) {
+	cursor, err := c.co().Indexes().List(ctx)
+	if err != nil {
+		return false, err
+	}
+	var indexes []struct {
+		Key     bson.D `bson:"key"`
+		Weights bson.M `bson:"weights"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return false, err
+	}
+	for _, index := range indexes {
+		for _, key := range index.Key {
+			if key.Value != "text" {
+				continue
+			}
+			// the key of a text index is _fts, the indexed fields are its weights
+			if _, ok := index.Weights[field]; ok {
+				return true, nil
+			}
+			if _, ok := index.Weights["$**"]; ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
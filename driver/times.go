@@ -0,0 +1,71 @@
+package driver
+
+import (
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var tTime = reflect.TypeOf(time.Time{})
+
+/*
+Returns the current time at the precision of BSON dates, in UTC
+Objects holding it compare equal to the same objects read back from the database
+
+Returns:
+
+	the current time - time.Time
+*/
+func Now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
+
+/*
+Decodes the dates of the documents into time.Time values of a location
+They are decoded in UTC by default
+
+	*time.Location location of the decoded times. ex: time.Local
+
+Returns:
+
+	an option - Option
+*/
+func WithTimeLocation(loc *time.Location) Option {
+	return func(cf *config) {
+		cf.codecs = append(cf.codecs, func(rb *bsoncodec.RegistryBuilder) {
+			rb.RegisterTypeDecoder(tTime, bsoncodec.ValueDecoderFunc(func(dc bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+				if err := bsoncodec.NewTimeCodec().DecodeValue(dc, vr, val); err != nil {
+					return err
+				}
+				val.Set(reflect.ValueOf(val.Interface().(time.Time).In(loc)))
+				return nil
+			}))
+		})
+	}
+}
+
+/*
+Truncates the time.Time values to a precision when encoding them
+BSON dates hold milliseconds, use a larger precision to store coarser times. ex: time.Second
+
+	time.Duration precision of the stored times
+
+Returns:
+
+	an option - Option
+*/
+func WithTimeTruncation(precision time.Duration) Option {
+	return func(cf *config) {
+		cf.codecs = append(cf.codecs, func(rb *bsoncodec.RegistryBuilder) {
+			rb.RegisterTypeEncoder(tTime, bsoncodec.ValueEncoderFunc(func(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+				if !val.IsValid() || val.Type() != tTime {
+					return bsoncodec.ValueEncoderError{Name: "TimeEncodeValue", Types: []reflect.Type{tTime}, Received: val}
+				}
+				return vw.WriteDateTime(int64(primitive.NewDateTimeFromTime(val.Interface().(time.Time).Truncate(precision))))
+			}))
+		})
+	}
+}
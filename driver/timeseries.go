@@ -0,0 +1,217 @@
+package driver
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+TimeSeries object
+Describes a time series collection
+
+	TimeField: field holding the time of each measurement
+
+	MetaField: field identifying the series, ex: a sensor id
+
+	Granularity: expected interval between measurements, "seconds", "minutes" or "hours"
+
+	ExpireAfter: delete measurements older than this, 0 to keep them
+*/
+type TimeSeries struct {
+	TimeField   string
+	MetaField   string
+	Granularity string
+	ExpireAfter time.Duration
+}
+
+/*
+BucketQuery object
+Describes a query grouping measurements into time buckets
+
+	TimeField: field holding the time of each measurement
+
+	MetaField: also group by this field when set
+
+	From: start of the range, inclusive
+
+	To: end of the range, exclusive
+
+	Unit: unit of the buckets. ex: "minute", "hour", "day"
+
+	BinSize: number of units in a bucket, 1 when 0
+
+	Filter: filter the measurements must match
+
+	Fields: accumulated fields of each bucket. ex: bson.D{{Key: "avg", Value: Avg("$temp")}}
+*/
+type BucketQuery struct {
+	TimeField string
+	MetaField string
+	From      time.Time
+	To        time.Time
+	Unit      string
+	BinSize   int
+	Filter    interface{}
+	Fields    bson.D
+}
+
+/*
+Creates a time series collection in the database
+
+	string: name of the collection
+
+	TimeSeries settings of the collection
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) CreateTimeSeriesCollection(name string, ts TimeSeries) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if ts.TimeField == "" {
+		return errors.New("time series collections need a time field")
+	}
+	tso := options.TimeSeries().SetTimeField(ts.TimeField)
+	if ts.MetaField != "" {
+		tso.SetMetaField(ts.MetaField)
+	}
+	if ts.Granularity != "" {
+		tso.SetGranularity(ts.Granularity)
+	}
+	opts := options.CreateCollection().SetTimeSeriesOptions(tso)
+	if ts.ExpireAfter > 0 {
+		opts.SetExpireAfterSeconds(int64(ts.ExpireAfter / time.Second))
+	}
+	return c.CreateCollection(name, opts)
+}
+
+/*
+Inserts measurements into a time series collection
+Measurements are inserted unordered so the server can write them to buckets in parallel
+
+	[]interface{} measurements to insert
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) InsertMeasurements(measurements []interface{}) (err error) {
+	defer c.observe("insertMeasurements", time.Now(), &err)
+	if err := c.collection("inserting measurements"); err != nil {
+		return err
+	}
+	defer c.invalidate()
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if len(measurements) == 0 {
+		return nil
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	docs, _, err := c.prepareInserts(measurements)
+	if err != nil {
+		return err
+	}
+	// ping database
+	if err := c.ping(); err != nil {
+		return err
+	}
+	if c.cf.dryRun != nil {
+		_, err := c.dryRun(ctx, "insertMeasurements", nil, docs, false)
+		return err
+	}
+	_, err = c.co().InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	return mapError(err)
+}
+
+/*
+Finds the measurements in a time range sorted by time
+
+	string: field holding the time of each measurement
+
+	time.Time start of the range, inclusive
+
+	time.Time end of the range, exclusive
+
+	interface{} pointer to a slice to decode the measurements into
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) FindRange(timeField string, from time.Time, to time.Time, results interface{}) (err error) {
+	defer c.observe("findRange", time.Now(), &err)
+	if err := c.collection("finding measurements"); err != nil {
+		return err
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return err
+	}
+	filter := F(timeField).Gte(from).Lt(to)
+	cursor, err := c.co().Find(ctx, filter, c.cf.findOptions(), options.Find().SetSort(Sort().Asc(timeField)))
+	if err != nil {
+		return mapError(err)
+	}
+	return c.all(ctx, cursor, results)
+}
+
+/*
+Groups the measurements of a time range into buckets and decodes them into results
+Each result has the start of its bucket in _id.time, and the series in _id.meta
+when a MetaField is set, followed by the accumulated fields. Requires MongoDB 5.0
+
+	BucketQuery query to run
+
+	interface{} pointer to a slice to decode the buckets into
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) Buckets(q BucketQuery, results interface{}) (err error) {
+	defer c.observe("buckets", time.Now(), &err)
+	if err := c.collection("bucketing measurements"); err != nil {
+		return err
+	}
+	if q.TimeField == "" || q.Unit == "" {
+		return errors.New("bucket queries need a TimeField and a Unit")
+	}
+	binSize := q.BinSize
+	if binSize == 0 {
+		binSize = 1
+	}
+	var match interface{} = F(q.TimeField).Gte(q.From).Lt(q.To)
+	if q.Filter != nil {
+		match = bson.D{{Key: "$and", Value: bson.A{match, q.Filter}}}
+	}
+	id := bson.D{{Key: "time", Value: bson.D{{Key: "$dateTrunc", Value: bson.D{
+		{Key: "date", Value: "$" + q.TimeField},
+		{Key: "unit", Value: q.Unit},
+		{Key: "binSize", Value: binSize},
+	}}}}}
+	if q.MetaField != "" {
+		id = append(id, bson.E{Key: "meta", Value: "$" + q.MetaField})
+	}
+	p := Pipeline().Match(match).Group(id, q.Fields).Sort(Sort().Asc("_id.time"))
+
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return err
+	}
+	cursor, err := c.co().Aggregate(ctx, p.Stages(), c.cf.aggregateOptions())
+	if err != nil {
+		return mapError(err)
+	}
+	return c.all(ctx, cursor, results)
+}
@@ -0,0 +1,93 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+/*
+Types of topology events
+*/
+const (
+	EventServerOpening   = "serverOpening"
+	EventServerClosed    = "serverClosed"
+	EventServerChanged   = "serverChanged"
+	EventTopologyChanged = "topologyChanged"
+)
+
+/*
+TopologyEvent object
+A change of the servers of the cluster seen by the driver
+
+	Type: type of the event. ex: EventServerChanged
+
+	Address: address of the server, empty for EventTopologyChanged
+
+	Previous: kind of the server or topology before the change, empty for EventServerOpening and EventServerClosed. ex: "RSPrimary"
+
+	Current: kind of the server or topology after the change, empty for EventServerOpening and EventServerClosed. ex: "RSSecondary" when a primary steps down
+
+	Time: time the event was received
+*/
+type TopologyEvent struct {
+	Type     string
+	Address  string
+	Previous string
+	Current  string
+	Time     time.Time
+}
+
+/*
+Registers a callback called when the servers of the cluster change, ex: to log failovers
+It receives a server being added or removed, a server changing kind (ex: RSPrimary to RSSecondary)
+and the topology changing kind (ex: ReplicaSetWithPrimary to ReplicaSetNoPrimary).
+Heartbeats and description refreshes that keep the same kind are not forwarded.
+The callbacks run on the driver's monitoring goroutines and must return quickly.
+They apply to connections made after they are registered
+
+	func(TopologyEvent): callback receiving the events
+*/
+func (c *Client) OnTopologyEvent(callback func(TopologyEvent)) {
+	c.cn.mu.Lock()
+	defer c.cn.mu.Unlock()
+	c.cn.topology = append(c.cn.topology, callback)
+}
+
+/*
+Monitor forwarding the events of the driver to the callbacks
+*/
+func (cn *connection) serverMonitor() *event.ServerMonitor {
+	return &event.ServerMonitor{
+		ServerOpening: func(e *event.ServerOpeningEvent) {
+			cn.emit(TopologyEvent{Type: EventServerOpening, Address: e.Address.String()})
+		},
+		ServerClosed: func(e *event.ServerClosedEvent) {
+			cn.emit(TopologyEvent{Type: EventServerClosed, Address: e.Address.String()})
+		},
+		ServerDescriptionChanged: func(e *event.ServerDescriptionChangedEvent) {
+			previous, current := e.PreviousDescription.Kind.String(), e.NewDescription.Kind.String()
+			if previous == current { // heartbeats refresh the description without changing it
+				return
+			}
+			cn.emit(TopologyEvent{Type: EventServerChanged, Address: e.Address.String(), Previous: previous, Current: current})
+		},
+		TopologyDescriptionChanged: func(e *event.TopologyDescriptionChangedEvent) {
+			previous, current := e.PreviousDescription.Kind.String(), e.NewDescription.Kind.String()
+			if previous == current {
+				return
+			}
+			cn.emit(TopologyEvent{Type: EventTopologyChanged, Previous: previous, Current: current})
+		},
+	}
+}
+
+func (cn *connection) emit(e TopologyEvent) {
+	e.Time = time.Now()
+	cn.mu.Lock()
+	callbacks := cn.topology
+	cn.mu.Unlock()
+	for _, callback := range callbacks {
+		callback(e)
+	}
+}
@@ -0,0 +1,136 @@
+package driver
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Makes documents expire a duration after the time stored in a field
+Creates a TTL index on the field, the server deletes expired documents
+about once a minute. Documents without a date in the field never expire
+
+	string: date field the expiry is computed from. ex: createdAt
+
+	time.Duration how long documents are kept
+
+Returns:
+
+	the name of the index - string
+
+	an err - error
+*/
+func (c *Client) ExpireAfter(field string, ttl time.Duration) (string, error) {
+	if err := c.writable(); err != nil {
+		return "", err
+	}
+	return c.CreateIndex(bson.D{{Key: field, Value: 1}}, options.Index().SetExpireAfterSeconds(int32(ttl/time.Second)))
+}
+
+/*
+Makes documents expire at the time stored in a field
+Creates a TTL index on the field so each document can have its own expiry,
+use InsertExpiringAt or InsertExpiringIn to set it
+
+	string: date field holding the expiry. ex: expiresAt
+
+Returns:
+
+	the name of the index - string
+
+	an err - error
+*/
+func (c *Client) ExpireAt(field string) (string, error) {
+	if err := c.writable(); err != nil {
+		return "", err
+	}
+	return c.CreateIndex(bson.D{{Key: field, Value: 1}}, options.Index().SetExpireAfterSeconds(0))
+}
+
+/*
+Inserts an object that expires at a given time
+The collection needs a TTL index created with ExpireAt on the field
+
+	interface{} object to insert
+
+	string: date field holding the expiry
+
+	time.Time when the object expires
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) InsertExpiringAt(object interface{}, field string, at time.Time) error {
+	if err := c.collection("inserting an object"); err != nil {
+		return err
+	}
+	if err := c.writable(); err != nil {
+		return err
+	}
+	object, err := c.prepare(object)
+	if err != nil {
+		return err
+	}
+	doc, err := withField(c.cf.codecRegistry(), object, field, at)
+	if err != nil {
+		return err
+	}
+	_, err = c.insertOne(doc, nil)
+	return err
+}
+
+/*
+Inserts an object that expires after a duration
+The collection needs a TTL index created with ExpireAt on the field
+
+	interface{} object to insert
+
+	string: date field holding the expiry
+
+	time.Duration how long the object is kept
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) InsertExpiringIn(object interface{}, field string, ttl time.Duration) error {
+	return c.InsertExpiringAt(object, field, time.Now().Add(ttl))
+}
+
+/*
+Converts an object to a document with a field set to a value
+The field is replaced if the object already has it
+
+	interface{} object to convert
+
+	string: field to set
+
+	interface{} value of the field
+
+Returns:
+
+	the document - bson.D
+
+	an err - error
+*/
+func withField(reg *bsoncodec.Registry, object interface{}, field string, value interface{}) (bson.D, error) {
+	b, err := bson.MarshalWithRegistry(reg, object)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	for i := range doc {
+		if doc[i].Key == field {
+			doc[i].Value = value
+			return doc, nil
+		}
+	}
+	return append(doc, bson.E{Key: field, Value: value}), nil
+}
@@ -0,0 +1,296 @@
+package driver
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+UpdateBuilder object
+Builds an update document. ex: Update().Set("name", "ada").Inc("logins", 1)
+*/
+type UpdateBuilder struct {
+	d bson.D
+}
+
+/*
+Creates a new update document
+
+Returns:
+
+	*UpdateBuilder pointer to an update builder
+*/
+func Update() *UpdateBuilder {
+	return &UpdateBuilder{d: bson.D{}}
+}
+
+/*
+Sets the field to the value
+*/
+func (u *UpdateBuilder) Set(field string, value interface{}) *UpdateBuilder {
+	return u.op("$set", field, value)
+}
+
+/*
+Sets the field to the value only when the update inserts a document
+*/
+func (u *UpdateBuilder) SetOnInsert(field string, value interface{}) *UpdateBuilder {
+	return u.op("$setOnInsert", field, value)
+}
+
+/*
+Removes the fields from the document
+*/
+func (u *UpdateBuilder) Unset(fields ...string) *UpdateBuilder {
+	for _, field := range fields {
+		u.op("$unset", field, "")
+	}
+	return u
+}
+
+/*
+Increments the field by delta, use a negative delta to decrement
+*/
+func (u *UpdateBuilder) Inc(field string, delta interface{}) *UpdateBuilder {
+	return u.op("$inc", field, delta)
+}
+
+/*
+Appends the values to an array field
+*/
+func (u *UpdateBuilder) Push(field string, values ...interface{}) *UpdateBuilder {
+	return u.op("$push", field, each(values))
+}
+
+/*
+Removes the elements equal to the value, or matching it if it is a condition, from an array field
+*/
+func (u *UpdateBuilder) Pull(field string, value interface{}) *UpdateBuilder {
+	return u.op("$pull", field, value)
+}
+
+/*
+Appends the values to an array field unless they are already in it
+*/
+func (u *UpdateBuilder) AddToSet(field string, values ...interface{}) *UpdateBuilder {
+	return u.op("$addToSet", field, each(values))
+}
+
+/*
+Returns the update document
+
+Returns:
+
+	a document - bson.D
+*/
+func (u *UpdateBuilder) D() bson.D {
+	return u.d
+}
+
+/*
+Lets the builder be passed directly wherever an update document is expected
+*/
+func (u *UpdateBuilder) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(u.d)
+}
+
+/*
+Adds a field to an operator, grouping fields of the same operator together
+*/
+func (u *UpdateBuilder) op(op string, field string, value interface{}) *UpdateBuilder {
+	for i := range u.d {
+		if u.d[i].Key == op {
+			u.d[i].Value = append(u.d[i].Value.(bson.D), bson.E{Key: field, Value: value})
+			return u
+		}
+	}
+	u.d = append(u.d, bson.E{Key: op, Value: bson.D{{Key: field, Value: value}}})
+	return u
+}
+
+/*
+Creates an update setting the non-zero fields of a struct, ex: for PATCH endpoints
+Pointer fields are set when they are not nil, even to a zero value, so use
+pointers for the fields that can be cleared. Field names follow the bson tags,
+fields tagged "-" and the _id are skipped and the fields of inline structs are set one by one
+
+	interface{} struct or pointer to a struct holding the changes
+
+Returns:
+
+	*UpdateBuilder pointer to an update builder
+
+	an err - error
+*/
+func SetFromStruct(v interface{}) (*UpdateBuilder, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, errors.New("SetFromStruct needs a struct or a pointer to a struct")
+	}
+	u := Update()
+	setFields(u, rv)
+	return u, nil
+}
+
+/*
+Adds a $set of every non-zero field of a struct value
+*/
+func setFields(u *UpdateBuilder, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, flags, _ := strings.Cut(field.Tag.Get("bson"), ",")
+		if name == "-" {
+			continue
+		}
+		value := v.Field(i)
+		if strings.Contains(flags, "inline") {
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				setFields(u, value)
+				continue
+			}
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name) // default name of the bson codec
+		}
+		if name == "_id" {
+			continue
+		}
+		switch value.Kind() {
+		case reflect.Pointer, reflect.Interface:
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		default:
+			if value.IsZero() {
+				continue
+			}
+		}
+		u.Set(name, value.Interface())
+	}
+}
+
+/*
+Path to the first array element matched by the filter of the update. ex: Positional("grades") + ".score"
+*/
+func Positional(array string) string {
+	return array + ".$"
+}
+
+/*
+Path to every element of an array. ex: PositionalAll("grades") + ".score"
+*/
+func PositionalAll(array string) string {
+	return array + ".$[]"
+}
+
+/*
+Path to the array elements matched by the array filter on identifier, see WithArrayFilters
+ex: Update().Set(PositionalFiltered("grades", "g")+".score", 100)
+*/
+func PositionalFiltered(array string, identifier string) string {
+	return array + ".$[" + identifier + "]"
+}
+
+/*
+Wraps multiple values in $each so they are added one by one
+*/
+func each(values []interface{}) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+	return bson.D{{Key: "$each", Value: bson.A(values)}}
+}
+
+/*
+Checks that an update only contains update operators
+Update pipelines are not checked
+
+	interface{} update to check
+
+Returns:
+
+	an err - error
+*/
+func ValidateUpdate(update interface{}) error {
+	keys, err := topLevelKeys(update)
+	if err != nil || keys == nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return errors.New("update document is empty")
+	}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "$") {
+			return errors.New("update document contains the field " + key + " which is not an update operator, use ReplaceOne to replace a document")
+		}
+	}
+	return nil
+}
+
+/*
+Checks that a replacement doesn't contain update operators
+
+	interface{} replacement to check
+
+Returns:
+
+	an err - error
+*/
+func ValidateReplacement(replacement interface{}) error {
+	keys, err := topLevelKeys(replacement)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if strings.HasPrefix(key, "$") {
+			return errors.New("replacement document contains the update operator " + key + ", use UpdateOne to update a document")
+		}
+	}
+	return nil
+}
+
+/*
+Top level keys of a document, nil if the value is a pipeline
+*/
+func topLevelKeys(document interface{}) ([]string, error) {
+	switch document.(type) {
+	case bson.D, bson.M, bson.Raw:
+	case mongo.Pipeline:
+		return nil, nil
+	default:
+		if v := reflect.ValueOf(document); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			return nil, nil
+		}
+	}
+	b, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := bson.Raw(b).Elements()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(elements))
+	for _, e := range elements {
+		keys = append(keys, e.Key())
+	}
+	return keys, nil
+}
@@ -0,0 +1,126 @@
+package driver
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		update *UpdateBuilder
+		want   string
+	}{
+		{"empty", Update(), `{}`},
+		{"set", Update().Set("name", "ada"), `{"$set":{"name":"ada"}}`},
+		{"grouped by operator", Update().Set("a", 1).Inc("n", 1).Set("b", 2), `{"$set":{"a":1,"b":2},"$inc":{"n":1}}`},
+		{"unset", Update().Unset("a", "b"), `{"$unset":{"a":"","b":""}}`},
+		{"push one", Update().Push("tags", "a"), `{"$push":{"tags":"a"}}`},
+		{"push many", Update().Push("tags", "a", "b"), `{"$push":{"tags":{"$each":["a","b"]}}}`},
+		{"addToSet many", Update().AddToSet("tags", "a", "b"), `{"$addToSet":{"tags":{"$each":["a","b"]}}}`},
+		{"pull", Update().Pull("tags", "a"), `{"$pull":{"tags":"a"}}`},
+		{"setOnInsert", Update().SetOnInsert("created", 1), `{"$setOnInsert":{"created":1}}`},
+		{"positional", Update().Set(Positional("grades")+".score", 1), `{"$set":{"grades.$.score":1}}`},
+		{"positional all", Update().Inc(PositionalAll("grades")+".score", 1), `{"$inc":{"grades.$[].score":1}}`},
+		{"positional filtered", Update().Set(PositionalFiltered("grades", "g")+".score", 1), `{"$set":{"grades.$[g].score":1}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extJSON(tt.update); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFromStruct(t *testing.T) {
+	type Address struct {
+		City string `bson:"city"`
+	}
+	type Patch struct {
+		ID      string   `bson:"_id"`
+		Name    string   `bson:"name"`
+		Age     int      `bson:"age,omitempty"`
+		Active  *bool    `bson:"active"`
+		Secret  string   `bson:"-"`
+		Nick    string   // default name of the codec
+		Address *Address `bson:",inline"`
+		hidden  string
+	}
+	no := false
+
+	tests := []struct {
+		name  string
+		patch interface{}
+		want  string
+	}{
+		{"zero fields are skipped", Patch{Name: "ada"}, `{"$set":{"name":"ada"}}`},
+		{"pointers are set to zero values", &Patch{Active: &no}, `{"$set":{"active":false}}`},
+		{"_id and - are skipped", Patch{ID: "1", Secret: "s", Age: 3}, `{"$set":{"age":3}}`},
+		{"default names", Patch{Nick: "a"}, `{"$set":{"nick":"a"}}`},
+		{"inline structs", Patch{Address: &Address{City: "Paris"}, hidden: "h"}, `{"$set":{"city":"Paris"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := SetFromStruct(tt.patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := extJSON(u); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SetFromStruct(map[string]string{"a": "b"}); err == nil {
+		t.Error("a map was accepted")
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		update  interface{}
+		wantErr bool
+	}{
+		{"operators", bson.D{{Key: "$set", Value: bson.D{{Key: "a", Value: 1}}}}, false},
+		{"builder", Update().Inc("n", 1), false},
+		{"map", bson.M{"$unset": bson.M{"a": ""}}, false},
+		{"pipelines aren't checked", mongo.Pipeline{{{Key: "$set", Value: bson.D{}}}}, false},
+		{"slices aren't checked", []bson.D{{{Key: "a", Value: 1}}}, false},
+		{"empty", bson.D{}, true},
+		{"replacement", bson.D{{Key: "a", Value: 1}}, true},
+		{"mixed", bson.D{{Key: "$set", Value: bson.D{}}, {Key: "a", Value: 1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateUpdate(tt.update); (err != nil) != tt.wantErr {
+				t.Errorf("got err %v, want an error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateReplacement(t *testing.T) {
+	tests := []struct {
+		name        string
+		replacement interface{}
+		wantErr     bool
+	}{
+		{"document", bson.D{{Key: "a", Value: 1}}, false},
+		{"struct", struct {
+			A int `bson:"a"`
+		}{1}, false},
+		{"empty", bson.D{}, false},
+		{"operator", bson.D{{Key: "a", Value: 1}, {Key: "$set", Value: bson.D{}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateReplacement(tt.replacement); (err != nil) != tt.wantErr {
+				t.Errorf("got err %v, want an error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package driver
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+/*
+Stores UUID types as BSON binary subtype 4 and decodes them back
+The types must be 16 byte arrays, ex: WithUUIDCodec(reflect.TypeOf(uuid.UUID{})) for
+google/uuid. Decoding also accepts the legacy subtype 3 and UUID strings, so documents
+written before the codec was added can still be read. Other types are ignored
+
+	...reflect.Type: UUID types to register
+
+Returns:
+
+	an option - Option
+*/
+func WithUUIDCodec(types ...reflect.Type) Option {
+	return func(cf *config) {
+		for _, t := range types {
+			if t.Kind() != reflect.Array || t.Len() != 16 || t.Elem().Kind() != reflect.Uint8 {
+				continue
+			}
+			t := t
+			cf.codecs = append(cf.codecs, func(rb *bsoncodec.RegistryBuilder) {
+				rb.RegisterTypeEncoder(t, bsoncodec.ValueEncoderFunc(encodeUUID))
+				rb.RegisterTypeDecoder(t, bsoncodec.ValueDecoderFunc(decodeUUID))
+			})
+		}
+	}
+}
+
+func encodeUUID(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	b := make([]byte, 16)
+	reflect.Copy(reflect.ValueOf(b), val)
+	return vw.WriteBinaryWithSubtype(b, bsontype.BinaryUUID)
+}
+
+func decodeUUID(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() {
+		return bsoncodec.ValueDecoderError{Name: "UUIDDecodeValue", Kinds: []reflect.Kind{reflect.Array}, Received: val}
+	}
+	var b []byte
+	switch vr.Type() {
+	case bsontype.Null:
+		val.Set(reflect.Zero(val.Type()))
+		return vr.ReadNull()
+	case bsontype.Binary:
+		data, subtype, err := vr.ReadBinary()
+		if err != nil {
+			return err
+		}
+		if subtype != bsontype.BinaryUUID && subtype != bsontype.BinaryUUIDOld {
+			return fmt.Errorf("cannot decode binary subtype %d into a UUID", subtype)
+		}
+		b = data
+	case bsontype.String:
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		if b, err = hex.DecodeString(strings.ReplaceAll(s, "-", "")); err != nil {
+			return fmt.Errorf("%q is not a UUID", s)
+		}
+	default:
+		return fmt.Errorf("cannot decode %v into a UUID", vr.Type())
+	}
+	if len(b) != 16 {
+		return fmt.Errorf("a UUID has 16 bytes, got %d", len(b))
+	}
+	reflect.Copy(val, reflect.ValueOf(b))
+	return nil
+}
@@ -0,0 +1,67 @@
+package driver
+
+import (
+	"fmt"
+)
+
+/*
+Validator interface
+Implemented by models that check themselves before they are written, ex:
+func (u *User) Validate() error { if u.Email == "" { return errors.New("email is required") }; return nil }
+*/
+type Validator interface {
+	Validate() error
+}
+
+/*
+An error returned by a validator, matching ErrInvalidDocument and wrapping the error of the validator
+*/
+func invalid(err error) error {
+	return &clientError{kind: ErrInvalidDocument, err: err}
+}
+
+/*
+Validates the documents before they are inserted or replaced, after their own Validate
+Plug in a struct validator. ex: WithValidator(validator.New().Struct)
+
+	func(interface{}) error: checks a document, returns an error when it is invalid
+
+Returns:
+
+	an option - Option
+*/
+func WithValidator(validate func(interface{}) error) Option {
+	return func(cf *config) {
+		cf.validator = validate
+	}
+}
+
+/*
+Checks a document before a write with its Validate method and the validator of
+the client. The errors match ErrInvalidDocument and wrap the error of the check
+*/
+func (c *Client) validate(object interface{}) error {
+	if v, ok := object.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return invalid(err)
+		}
+	}
+	if c.cf.validator != nil {
+		if err := c.cf.validator(object); err != nil {
+			return invalid(err)
+		}
+	}
+	return nil
+}
+
+/*
+Checks a list of documents, the error gives the index of the first invalid one
+*/
+func (c *Client) validateAll(objects []interface{}) error {
+	for i, object := range objects {
+		if err := c.validate(object); err != nil {
+			return fmt.Errorf("object %d: %w", i, err)
+		}
+	}
+	return nil
+}
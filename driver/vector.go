@@ -0,0 +1,99 @@
+package driver
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+/*
+VectorQuery object
+Describes an Atlas Vector Search query
+
+	Index: name of the vector search index
+
+	Path: field holding the embeddings
+
+	Vector: embedding to find the nearest neighbours of
+
+	NumCandidates: number of neighbours to consider, defaults to 10 times Limit
+
+	Limit: number of results to return
+
+	Filter: pre-filter on fields indexed with the filter type
+
+	ScoreField: field to put the similarity score in, "score" when empty
+*/
+type VectorQuery struct {
+	Index         string
+	Path          string
+	Vector        []float64
+	NumCandidates int64
+	Limit         int64
+	Filter        interface{}
+	ScoreField    string
+}
+
+/*
+Adds a $vectorSearch stage followed by the score of each result
+It must be the first stage of the pipeline
+*/
+func (p *PipelineBuilder) VectorSearch(q VectorQuery) *PipelineBuilder {
+	candidates := q.NumCandidates
+	if candidates == 0 {
+		candidates = q.Limit * 10
+	}
+	spec := bson.D{
+		{Key: "index", Value: q.Index},
+		{Key: "path", Value: q.Path},
+		{Key: "queryVector", Value: q.Vector},
+		{Key: "numCandidates", Value: candidates},
+		{Key: "limit", Value: q.Limit},
+	}
+	if q.Filter != nil {
+		spec = append(spec, bson.E{Key: "filter", Value: q.Filter})
+	}
+	score := q.ScoreField
+	if score == "" {
+		score = "score"
+	}
+	return p.Stage("$vectorSearch", spec).
+		AddFields(bson.D{{Key: score, Value: bson.D{{Key: "$meta", Value: "vectorSearchScore"}}}})
+}
+
+/*
+Finds the documents with the nearest embeddings and decodes them into results
+Each result holds its similarity score in the ScoreField of the query
+
+	VectorQuery query to run
+
+	interface{} pointer to a slice to decode the results into
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) VectorSearch(q VectorQuery, results interface{}) (err error) {
+	defer c.observe("vectorSearch", time.Now(), &err)
+	if err := c.collection("running a vector search"); err != nil {
+		return err
+	}
+	if q.Index == "" || q.Path == "" || len(q.Vector) == 0 {
+		return errors.New("vector search needs an Index, a Path and a Vector")
+	}
+	if q.Limit < 1 {
+		return errors.New("vector search needs a Limit greater than 0")
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	// ping database
+	if err := c.ping(); err != nil {
+		return err
+	}
+	cursor, err := c.co().Aggregate(ctx, Pipeline().VectorSearch(q).Stages(), c.cf.aggregateOptions())
+	if err != nil {
+		return mapError(err)
+	}
+	return c.all(ctx, cursor, results)
+}
@@ -0,0 +1,103 @@
+package driver
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Creates a read-only view of the database, computed from a collection by a pipeline
+The view can be read like a collection with SetCollection
+
+	string: name of the view
+
+	string: collection or view the view reads from
+
+	interface{} pipeline computing the view, ex: Pipeline().Match(filter).Project(projection).Stages()
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) CreateView(name string, source string, pipeline interface{}) (err error) {
+	defer c.observe("createView", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if c.db() == nil {
+		return noDatabase("creating a view")
+	}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("createView", bson.D{{Key: "create", Value: name}, {Key: "viewOn", Value: source}, {Key: "pipeline", Value: pipeline}})
+	}
+	opts := options.CreateView()
+	if c.cf.collation != nil {
+		opts.SetCollation(c.cf.collation)
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	return mapError(c.db().CreateView(ctx, name, source, pipeline, opts))
+}
+
+/*
+Drops a view of the database
+Returns an error if the name is a collection, use DropCollection to drop it
+
+	string: name of the view
+
+Returns:
+
+	an err - error
+*/
+func (c *Client) DropView(name string) (err error) {
+	defer c.observe("dropView", time.Now(), &err)
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if c.db() == nil {
+		return noDatabase("dropping a view")
+	}
+	if c.cf.dryRun != nil {
+		return c.dryRunChange("dropView", bson.D{{Key: "drop", Value: name}})
+	}
+	var specs []struct {
+		Type string `bson:"type"`
+	}
+	ctx, cancel := c.operation()
+	defer cancel()
+	cursor, err := c.db().ListCollections(ctx, bson.D{{Key: "name", Value: name}})
+	if err != nil {
+		return mapError(err)
+	}
+	if err := cursor.All(ctx, &specs); err != nil {
+		return mapError(err)
+	}
+	// dropping a view that doesn't exist is not an error, like collections
+	if len(specs) == 0 {
+		return nil
+	}
+	if specs[0].Type != "view" {
+		return fmt.Errorf("%s is a collection, not a view", name)
+	}
+	if err := c.db().Collection(name).Drop(ctx); err != nil {
+		return mapError(err)
+	}
+	c.invalidateNamespace(c.db().Name() + "." + name)
+	return nil
+}
+
+/*
+Lists the names of the views in the database
+
+Returns:
+
+	the names of the views - []string
+
+	an err - error
+*/
+func (c *Client) ListViews() ([]string, error) {
+	return c.ListCollections(bson.D{{Key: "type", Value: "view"}})
+}
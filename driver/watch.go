@@ -0,0 +1,253 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+ChangeEvent object
+A change of the watched collection
+*/
+type ChangeEvent struct {
+	ID            bson.Raw            `bson:"_id"`
+	OperationType string              `bson:"operationType"`
+	ClusterTime   primitive.Timestamp `bson:"clusterTime"`
+	Namespace     struct {
+		DB         string `bson:"db"`
+		Collection string `bson:"coll"`
+	} `bson:"ns"`
+	DocumentKey       bson.Raw `bson:"documentKey"`
+	FullDocument      bson.Raw `bson:"fullDocument"`
+	UpdateDescription bson.Raw `bson:"updateDescription"`
+}
+
+/*
+TokenStore interface
+Keeps the resume token of watchers so they continue where they stopped after a restart
+*/
+type TokenStore interface {
+	Load(ctx context.Context, name string) (bson.Raw, error)
+	Save(ctx context.Context, name string, token bson.Raw) error
+}
+
+/*
+Watcher object
+Delivers the changes of a collection to a handler, resuming after restarts and invalidate events
+*/
+type Watcher struct {
+	c        *Client
+	name     string
+	pipeline interface{}
+	store    TokenStore
+}
+
+/*
+Stores resume tokens in a collection, one document per watcher
+*/
+type collectionTokenStore struct {
+	c  *Client
+	co *mongo.Collection
+}
+
+/*
+Creates a token store keeping the tokens in a collection of the database that is set
+Saving a token is a write, read-only clients fail and dry-run clients report it
+
+	string: name of the collection
+
+Returns:
+
+	a token store - TokenStore
+
+	an err - error
+*/
+func (c *Client) TokenStore(collection string) (TokenStore, error) {
+	if c.db() == nil {
+		return nil, noDatabase("creating a token store")
+	}
+	return &collectionTokenStore{c: c, co: c.db().Collection(collection, c.cf.collectionOptions())}, nil
+}
+
+func (s *collectionTokenStore) Load(ctx context.Context, name string) (bson.Raw, error) {
+	var doc struct {
+		Token bson.Raw `bson:"token"`
+	}
+	err := s.co.FindOne(ctx, bson.D{{Key: "_id", Value: name}}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return doc.Token, err
+}
+
+func (s *collectionTokenStore) Save(ctx context.Context, name string, token bson.Raw) error {
+	if err := s.c.writable(); err != nil {
+		return err
+	}
+	filter := bson.D{{Key: "_id", Value: name}}
+	update := Update().Set("token", token).Set("updatedAt", time.Now())
+	if s.c.cf.dryRun != nil { // the position of the watcher is kept
+		s.c.cf.dryRun(DryRunWrite{Operation: "saveToken", Filter: filter, Document: update.D()})
+		return nil
+	}
+	_, err := s.co.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return mapError(err)
+}
+
+/*
+Creates a watcher on the collection
+The name identifies the watcher in the store, watchers sharing a name share their position
+
+	string: name of the watcher
+
+	interface{} pipeline to filter the changes with, nil for every change
+
+	TokenStore store to keep the resume token in, see TokenStore
+
+Returns:
+
+	*Watcher pointer to a watcher
+
+	an err - error
+*/
+func (c *Client) Watch(name string, pipeline interface{}, store TokenStore) (*Watcher, error) {
+	if err := c.collection("watching it"); err != nil {
+		return nil, err
+	}
+	if pipeline == nil {
+		pipeline = mongo.Pipeline{}
+	}
+	return &Watcher{c: c, name: name, pipeline: pipeline, store: store}, nil
+}
+
+/*
+Delivers the changes to the handler until the context is done or an error occurs
+Delivery is at least once: the resume token is saved after the handler returns,
+so a change whose handler failed, or that was being handled when the process
+stopped, is delivered again on the next run. Handlers should be idempotent.
+When the collection is dropped or renamed the watcher resumes after the invalidate event
+
+	context.Context context to stop watching with
+
+	func(ChangeEvent) error handler called for every change, returning an error stops the watcher
+
+Returns:
+
+	an err - error
+*/
+func (w *Watcher) Run(ctx context.Context, handler func(ChangeEvent) error) error {
+	for {
+		token, err := w.store.Load(ctx, w.name)
+		if err != nil {
+			return err
+		}
+		if err := w.run(ctx, token, handler); err != nil {
+			return err
+		}
+	}
+}
+
+/*
+Runs one change stream, returns nil after an invalidate event
+*/
+func (w *Watcher) run(ctx context.Context, token bson.Raw, handler func(ChangeEvent) error) error {
+	if err := w.c.ping(); err != nil {
+		return err
+	}
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if token != nil {
+		// unlike resumeAfter, startAfter also resumes after an invalidate event
+		opts.SetStartAfter(token)
+	}
+	cs, err := w.c.co().Watch(ctx, w.pipeline, opts)
+	if err != nil {
+		return err
+	}
+	defer cs.Close(context.Background())
+
+	for cs.Next(ctx) {
+		var event ChangeEvent
+		if err := cs.Decode(&event); err != nil {
+			return err
+		}
+		if event.OperationType == "invalidate" {
+			return w.store.Save(ctx, w.name, event.ID)
+		}
+		if err := w.c.readChange(&event); err != nil {
+			return err
+		}
+		if err := handler(event); err != nil {
+			return err
+		}
+		if err := w.store.Save(ctx, w.name, event.ID); err != nil {
+			return err
+		}
+	}
+	if err := cs.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+/*
+Prepares a change before it is delivered like the documents of a find,
+redacting its full document and the fields of its update description
+*/
+func (c *Client) readChange(event *ChangeEvent) error {
+	if read := c.reader(); read != nil && event.FullDocument != nil {
+		doc, err := read(event.FullDocument)
+		if err != nil {
+			return err
+		}
+		event.FullDocument = doc
+	}
+	rules := c.cf.activeRedactions()
+	if len(rules) == 0 || event.UpdateDescription == nil {
+		return nil
+	}
+	var desc bson.D
+	if err := bson.Unmarshal(event.UpdateDescription, &desc); err != nil {
+		return err
+	}
+	for i := range desc {
+		if fields, ok := desc[i].Value.(bson.D); ok && desc[i].Key == "updatedFields" {
+			for _, rule := range rules {
+				fields = redactUpdatedFields(fields, rule)
+			}
+			desc[i].Value = fields
+		}
+	}
+	b, err := bson.Marshal(desc)
+	if err != nil {
+		return err
+	}
+	event.UpdateDescription = b
+	return nil
+}
+
+/*
+Redacts updated fields, their keys are dotted paths. ex: profile.ssn
+*/
+func redactUpdatedFields(fields bson.D, rule RedactRule) bson.D {
+	redacted := fields[:0]
+	for _, e := range fields {
+		switch {
+		case e.Key == rule.Path || strings.HasPrefix(e.Key, rule.Path+"."):
+			if rule.Mask == nil {
+				continue
+			}
+			e.Value = rule.Mask
+		case strings.HasPrefix(rule.Path, e.Key+"."):
+			// a parent of the field was set
+			e.Value = redactValue(e.Value, strings.Split(strings.TrimPrefix(rule.Path, e.Key+"."), "."), rule.Mask)
+		}
+		redacted = append(redacted, e)
+	}
+	return redacted
+}
@@ -0,0 +1,306 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/olympsis/go-mongo/driver"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+/*
+Flag object
+A feature flag as it is stored in the collection
+
+	Name: name of the flag
+
+	Enabled: turns the flag off for everyone when false
+
+	Rules: targeting rules, the first rule matching the attributes decides
+
+	Percentage: share of the keys the flag is on for, from 0 to 100. On for everyone when nil
+
+	KeyAttribute: attribute the percentage is computed on, "key" when empty. ex: "userId"
+*/
+type Flag struct {
+	Name         string    `bson:"_id"`
+	Enabled      bool      `bson:"enabled"`
+	Rules        []Rule    `bson:"rules,omitempty"`
+	Percentage   *float64  `bson:"percentage,omitempty"`
+	KeyAttribute string    `bson:"keyAttribute,omitempty"`
+	UpdatedAt    time.Time `bson:"updatedAt"`
+}
+
+/*
+Rule object
+Turns the flag on or off for the attributes with one of the values
+
+	Attribute: name of the attribute. ex: "country"
+
+	Values: values of the attribute matching the rule
+
+	Enabled: value of the flag when the rule matches
+*/
+type Rule struct {
+	Attribute string   `bson:"attribute"`
+	Values    []string `bson:"values"`
+	Enabled   bool     `bson:"enabled"`
+}
+
+/*
+Store object
+Keeps feature flags in a collection
+*/
+type Store struct {
+	c       *driver.Client
+	name    string
+	mu      sync.RWMutex
+	flags   map[string]Flag
+	pending []driver.ChangeEvent // changes received while Start loads the flags
+	sub     *driver.Subscription
+}
+
+/*
+Creates a store on a collection of the database set on the client
+
+	*driver.Client client with the database set
+
+	string: name of the collection of the flags
+
+Returns:
+
+	*Store pointer to a store
+
+	an err - error
+*/
+func New(c *driver.Client, collection string) (*Store, error) {
+	client := c.With()
+	if _, err := client.SetCollection(collection); err != nil {
+		return nil, err
+	}
+	return &Store{c: client, name: collection}, nil
+}
+
+/*
+Creates or replaces a flag
+
+	Flag flag to save
+
+Returns:
+
+	an err - error
+*/
+func (s *Store) Set(flag Flag) error {
+	flag.UpdatedAt = time.Now()
+	_, err := s.c.Upsert(driver.F("_id").Eq(flag.Name), flag)
+	return err
+}
+
+/*
+Deletes a flag
+
+	string: name of the flag
+
+Returns:
+
+	an err - error
+*/
+func (s *Store) Delete(name string) error {
+	if _, err := s.c.RemoveOne(driver.F("_id").Eq(name), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+/*
+Evaluates a flag for a set of attributes
+Uses the cache once Start has been called, otherwise reads the flag
+from the collection. Missing flags are off
+
+	context.Context context of the evaluation
+
+	string: name of the flag
+
+	map[string]string: attributes of the caller. ex: {"key": userID, "country": "FR"}
+
+Returns:
+
+	a boolean - bool
+
+	an err - error
+*/
+func (s *Store) Evaluate(ctx context.Context, name string, attributes map[string]string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	flag, ok, err := s.flag(name)
+	if err != nil || !ok {
+		return false, err
+	}
+	return flag.evaluate(attributes), nil
+}
+
+/*
+Loads every flag in memory and keeps them up to date with a change stream
+Evaluate no longer reads from the collection once it returns
+
+Returns:
+
+	an err - error
+*/
+func (s *Store) Start() error {
+	s.mu.Lock()
+	s.pending = []driver.ChangeEvent{}
+	s.mu.Unlock()
+	flags, sub, err := s.load()
+	s.mu.Lock()
+	if err != nil {
+		s.pending = nil
+		s.mu.Unlock()
+		return err
+	}
+	s.flags = map[string]Flag{}
+	for _, flag := range flags {
+		s.flags[flag.Name] = flag
+	}
+	// the changes made while loading may be missing from the flags
+	for _, event := range s.pending {
+		if err = s.applyLocked(event); err != nil {
+			s.flags = nil
+			break
+		}
+	}
+	s.pending = nil
+	if err == nil {
+		s.sub = sub
+	}
+	s.mu.Unlock()
+	if err != nil {
+		sub.Close() // waits for the handler, which takes the lock
+	}
+	return err
+}
+
+/*
+Subscribes to the changes of the collection, then reads every flag
+*/
+func (s *Store) load() ([]Flag, *driver.Subscription, error) {
+	sub, err := s.c.Subscribe(s.name, nil, s.apply, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor := s.c.FindMany(bson.D{}, nil)
+	if cursor == nil {
+		sub.Close()
+		return nil, nil, errors.New("could not load the flags")
+	}
+	var flags []Flag
+	if err := cursor.All(context.Background(), &flags); err != nil {
+		sub.Close()
+		return nil, nil, err
+	}
+	return flags, sub, nil
+}
+
+/*
+Stops keeping the flags up to date, Evaluate reads from the collection again
+*/
+func (s *Store) Close() {
+	s.mu.Lock()
+	sub := s.sub
+	s.sub, s.flags = nil, nil
+	s.mu.Unlock()
+	if sub != nil {
+		sub.Close()
+	}
+}
+
+/*
+Applies a change of the collection to the cache
+*/
+func (s *Store) apply(event driver.ChangeEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending != nil {
+		s.pending = append(s.pending, event)
+		return nil
+	}
+	return s.applyLocked(event)
+}
+
+func (s *Store) applyLocked(event driver.ChangeEvent) error {
+	var key struct {
+		ID string `bson:"_id"`
+	}
+	if err := bson.Unmarshal(event.DocumentKey, &key); err != nil {
+		return err
+	}
+	var flag Flag
+	if event.FullDocument != nil {
+		if err := bson.Unmarshal(event.FullDocument, &flag); err != nil {
+			return err
+		}
+	}
+	if s.flags == nil {
+		return nil
+	}
+	if event.FullDocument == nil { // deleted
+		delete(s.flags, key.ID)
+		return nil
+	}
+	s.flags[key.ID] = flag
+	return nil
+}
+
+func (s *Store) flag(name string) (Flag, bool, error) {
+	s.mu.RLock()
+	if s.flags != nil {
+		flag, ok := s.flags[name]
+		s.mu.RUnlock()
+		return flag, ok, nil
+	}
+	s.mu.RUnlock()
+
+	res := s.c.FindOne(driver.F("_id").Eq(name))
+	if res == nil {
+		return Flag{}, false, errors.New("could not connect to read the flag " + name)
+	}
+	var flag Flag
+	err := res.Decode(&flag)
+	if err == mongo.ErrNoDocuments {
+		return Flag{}, false, nil
+	}
+	return flag, err == nil, err
+}
+
+func (f *Flag) evaluate(attributes map[string]string) bool {
+	if !f.Enabled {
+		return false
+	}
+	for _, rule := range f.Rules {
+		value, ok := attributes[rule.Attribute]
+		if !ok {
+			continue
+		}
+		for _, v := range rule.Values {
+			if v == value {
+				return rule.Enabled
+			}
+		}
+	}
+	if f.Percentage == nil {
+		return true
+	}
+	attribute := f.KeyAttribute
+	if attribute == "" {
+		attribute = "key"
+	}
+	// the same key always falls in the same bucket so the flag doesn't flip between checks
+	h := fnv.New32a()
+	h.Write([]byte(f.Name + ":" + attributes[attribute]))
+	return float64(h.Sum32()%10000) < *f.Percentage*100
+}
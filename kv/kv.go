@@ -0,0 +1,155 @@
+package kv
+
+import (
+	"errors"
+	"time"
+
+	"github.com/olympsis/go-mongo/driver"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Error returned by Get when the key is missing or expired
+*/
+var ErrNotFound = driver.ErrNotFound
+
+/*
+Store object
+A key-value store on a collection, one document per key keyed by the key
+*/
+type Store struct {
+	c *driver.Client
+}
+
+/*
+Creates a store on the collection set on the client
+Expired keys are removed by a TTL index and hidden until they are
+
+	*driver.Client client with the collection of the keys set
+
+Returns:
+
+	*Store pointer to a store
+
+	an err - error
+*/
+func New(c *driver.Client) (*Store, error) {
+	if _, err := c.ExpireAt("expiresAt"); err != nil {
+		return nil, err
+	}
+	return &Store{c: c}, nil
+}
+
+/*
+Gets the value of a key
+
+	string: key to get
+
+	interface{} pointer to decode the value into
+
+Returns:
+
+	an err - error, ErrNotFound if the key is missing or expired
+*/
+func (s *Store) Get(key string, value interface{}) error {
+	var doc struct {
+		Value bson.RawValue `bson:"value"`
+	}
+	res := s.c.FindOne(live(key))
+	if res == nil {
+		return errors.New("could not connect to get the key " + key)
+	}
+	err := res.Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return doc.Value.Unmarshal(value)
+}
+
+/*
+Sets the value of a key
+
+	string: key to set
+
+	interface{} value of the key
+
+	time.Duration time after which the key expires, 0 to keep it forever
+
+Returns:
+
+	an err - error
+*/
+func (s *Store) Set(key string, value interface{}, ttl time.Duration) error {
+	doc := bson.D{{Key: "value", Value: value}}
+	if ttl > 0 {
+		doc = append(doc, bson.E{Key: "expiresAt", Value: time.Now().Add(ttl)})
+	}
+	_, err := s.c.Upsert(driver.F("_id").Eq(key), doc)
+	return err
+}
+
+/*
+Sets the value of a key only if it is missing or expired
+
+	string: key to set
+
+	interface{} value of the key
+
+	time.Duration time after which the key expires, 0 to keep it forever
+
+Returns:
+
+	a boolean, false if the key already had a value - bool
+
+	an err - error
+*/
+func (s *Store) SetNX(key string, value interface{}, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	update := driver.Update().Set("value", value)
+	if ttl > 0 {
+		update.Set("expiresAt", now.Add(ttl))
+	} else {
+		update.Unset("expiresAt")
+	}
+	// the upsert inserts a key with the same _id when the key is set, which fails on the unique _id index
+	filter := driver.And(driver.F("_id").Eq(key), driver.F("expiresAt").Lte(now))
+	err := s.c.FindOneAndUpdate(filter, update, options.FindOneAndUpdate().SetUpsert(true)).Err()
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil && err != mongo.ErrNoDocuments {
+		return false, err
+	}
+	return true, nil
+}
+
+/*
+Deletes a key
+
+	string: key to delete
+
+Returns:
+
+	an err - error
+*/
+func (s *Store) Delete(key string) error {
+	if _, err := s.c.RemoveOne(driver.F("_id").Eq(key), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+/*
+Filter matching a key that has not expired
+*/
+func live(key string) *driver.Filter {
+	return driver.And(
+		driver.F("_id").Eq(key),
+		driver.Or(driver.F("expiresAt").Exists(false), driver.F("expiresAt").Gt(time.Now())),
+	)
+}
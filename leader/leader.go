@@ -0,0 +1,134 @@
+package leader
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/olympsis/go-mongo/lock"
+)
+
+/*
+Elector object
+Elects one leader among the replicas running an elector with the same name
+The leader holds a lock which it renews with a heartbeat, when it stops
+renewing it another replica takes over once the lock expires
+*/
+type Elector struct {
+	l        *lock.Locker
+	name     string
+	ttl      time.Duration
+	leader   atomic.Bool
+	mu       sync.Mutex
+	elected  []func()
+	resigned []func()
+}
+
+/*
+Creates an elector
+
+	*lock.Locker locker to hold the leadership lock with
+
+	string: name of the election
+
+	time.Duration time a leader keeps the leadership without renewing it, the heartbeat runs every third of it
+
+Returns:
+
+	*Elector pointer to an elector
+*/
+func New(l *lock.Locker, name string, ttl time.Duration) *Elector {
+	return &Elector{l: l, name: name, ttl: ttl}
+}
+
+/*
+Registers a callback called when the replica becomes the leader
+*/
+func (e *Elector) OnElected(callback func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.elected = append(e.elected, callback)
+}
+
+/*
+Registers a callback called when the replica stops being the leader
+Stop the singleton jobs in it, the leadership may already be held by another replica
+*/
+func (e *Elector) OnResigned(callback func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resigned = append(e.resigned, callback)
+}
+
+/*
+Checks if the replica is the leader
+*/
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+/*
+Takes part in the election until the context is done
+The leadership is released when the context is done
+
+	context.Context context to stop with
+
+Returns:
+
+	an err - error
+*/
+func (e *Elector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	var held *lock.Lock
+	for {
+		if held == nil {
+			lk, err := e.l.Lock(e.name, e.ttl)
+			if err == nil {
+				held = lk
+				e.set(true)
+			}
+		} else if err := held.Extend(e.ttl); err == lock.ErrNotHeld {
+			held = nil
+			e.set(false)
+		} else if err != nil && !e.renewable(held) {
+			// we could not renew the lock before it expired
+			held = nil
+			e.set(false)
+		}
+
+		select {
+		case <-ctx.Done():
+			if held != nil {
+				held.Unlock()
+				e.set(false)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+/*
+Checks if a lock that failed to renew may still be held
+*/
+func (e *Elector) renewable(held *lock.Lock) bool {
+	return time.Until(held.ExpiresAt()) > 0
+}
+
+func (e *Elector) set(leader bool) {
+	if e.leader.Swap(leader) == leader {
+		return
+	}
+	e.mu.Lock()
+	callbacks := e.resigned
+	if leader {
+		callbacks = e.elected
+	}
+	e.mu.Unlock()
+	for _, callback := range callbacks {
+		callback()
+	}
+}
@@ -0,0 +1,157 @@
+package lock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/olympsis/go-mongo/driver"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Errors returned by the locks
+*/
+var (
+	ErrLocked  = errors.New("lock is held by another owner")
+	ErrNotHeld = errors.New("lock is not held anymore")
+)
+
+/*
+Locker object
+Acquires locks stored in a collection, one document per lock keyed by its name
+*/
+type Locker struct {
+	c *driver.Client
+}
+
+/*
+Lock object
+A lock acquired by a Locker
+*/
+type Lock struct {
+	l       *Locker
+	name    string
+	owner   string
+	expires time.Time
+}
+
+/*
+Creates a locker on the collection set on the client
+Expired locks are removed by a TTL index, they can be taken
+over as soon as they expire without waiting for it
+
+	*driver.Client client with the collection of the locks set
+
+Returns:
+
+	*Locker pointer to a locker
+
+	an err - error
+*/
+func New(c *driver.Client) (*Locker, error) {
+	if _, err := c.ExpireAt("expiresAt"); err != nil {
+		return nil, err
+	}
+	return &Locker{c: c}, nil
+}
+
+/*
+Acquires a lock for the ttl, the lock is released once the ttl expires unless it is extended
+
+	string: name of the lock
+
+	time.Duration time to hold the lock for
+
+Returns:
+
+	*Lock pointer to the lock
+
+	an err - error, ErrLocked if another owner holds the lock
+*/
+func (l *Locker) Lock(name string, ttl time.Duration) (*Lock, error) {
+	owner, err := newOwner()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	// the upsert inserts a lock with the same _id when the lock is held, which fails on the unique _id index
+	filter := driver.And(driver.F("_id").Eq(name), driver.F("expiresAt").Lte(now))
+	update := driver.Update().Set("owner", owner).Set("expiresAt", now.Add(ttl)).Set("acquiredAt", now)
+	err = l.c.FindOneAndUpdate(filter, update, options.FindOneAndUpdate().SetUpsert(true)).Err()
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrLocked
+	}
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	return &Lock{l: l, name: name, owner: owner, expires: now.Add(ttl)}, nil
+}
+
+/*
+Extends the lock for the ttl from now
+
+	time.Duration time to hold the lock for
+
+Returns:
+
+	an err - error, ErrNotHeld if the lock expired and was taken over
+*/
+func (lk *Lock) Extend(ttl time.Duration) error {
+	expires := time.Now().Add(ttl)
+	err := lk.l.c.FindOneAndUpdate(lk.filter(), driver.Update().Set("expiresAt", expires), nil).Err()
+	if err == mongo.ErrNoDocuments {
+		return ErrNotHeld
+	}
+	if err != nil {
+		return err
+	}
+	lk.expires = expires
+	return nil
+}
+
+/*
+Releases the lock
+A lock that expired and was taken over is left to its new owner
+
+Returns:
+
+	an err - error
+*/
+func (lk *Lock) Unlock() error {
+	if _, err := lk.l.c.RemoveOne(lk.filter(), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+/*
+Name of the lock
+*/
+func (lk *Lock) Name() string {
+	return lk.name
+}
+
+/*
+Time at which the lock expires unless it is extended
+*/
+func (lk *Lock) ExpiresAt() time.Time {
+	return lk.expires
+}
+
+func (lk *Lock) filter() *driver.Filter {
+	return driver.And(driver.F("_id").Eq(lk.name), driver.F("owner").Eq(lk.owner))
+}
+
+/*
+Random token identifying who holds a lock
+*/
+func newOwner() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,191 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olympsis/go-mongo/driver"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+States of an event
+*/
+const (
+	StatePending   = "pending"
+	StatePublished = "published"
+)
+
+/*
+Event object
+An event of the outbox as it is stored in the collection
+*/
+type Event struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	Topic       string             `bson:"topic"`
+	Payload     bson.Raw           `bson:"payload"`
+	State       string             `bson:"state"`
+	Attempts    int                `bson:"attempts"`
+	LastError   string             `bson:"lastError,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	PublishedAt time.Time          `bson:"publishedAt,omitempty"`
+}
+
+/*
+Outbox object
+Stores the events of a service in a collection so they are written in the
+same transaction as the documents they describe, then published by a relay
+*/
+type Outbox struct {
+	collection string
+}
+
+/*
+Tx object
+A transaction writing documents and events
+*/
+type Tx struct {
+	*driver.Client
+	events *driver.Client
+}
+
+/*
+Creates an outbox
+The collection must be in the same database as the documents written with it
+
+	string: name of the collection of the events
+
+Returns:
+
+	*Outbox pointer to an outbox
+*/
+func New(collection string) *Outbox {
+	return &Outbox{collection: collection}
+}
+
+/*
+Writes documents and events in a single transaction
+Use the Client of the transaction to write the documents and Emit to add events,
+nothing is written unless the function returns nil
+
+	*driver.Client client with the database and collection of the documents set
+
+	func(*Tx) error: function writing the documents and events
+
+Returns:
+
+	an err - error
+*/
+func (o *Outbox) Write(c *driver.Client, writes func(*Tx) error) error {
+	return c.Transaction(func(tc *driver.Client) error {
+		events := tc.With()
+		if _, err := events.SetCollection(o.collection); err != nil {
+			return err
+		}
+		return writes(&Tx{Client: tc, events: events})
+	})
+}
+
+/*
+Adds an event to the outbox as part of the transaction
+
+	string: topic of the event
+
+	interface{} payload of the event
+
+Returns:
+
+	an err - error
+*/
+func (tx *Tx) Emit(topic string, payload interface{}) error {
+	b, err := bson.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	event := Event{
+		ID:        primitive.NewObjectID(),
+		Topic:     topic,
+		Payload:   b,
+		State:     StatePending,
+		CreatedAt: time.Now(),
+	}
+	_, err = tx.events.InsertOne(event, nil)
+	return err
+}
+
+/*
+Publishes the pending events in the order they were written until the context is done
+An event is marked published once the callback returns nil, so an event may be
+published again if the relay stops in between. When the callback fails the
+relay retries the same event after the interval to keep the order.
+Run a single relay per outbox, see the leader package
+
+	context.Context context to stop with
+
+	*driver.Client client with the database set
+
+	func(Event) error: callback publishing an event
+
+	time.Duration time to wait when there are no pending events
+
+Returns:
+
+	an err - error
+*/
+func (o *Outbox) Relay(ctx context.Context, c *driver.Client, publish func(Event) error, interval time.Duration) error {
+	events := c.With()
+	if _, err := events.SetCollection(o.collection); err != nil {
+		return err
+	}
+	for {
+		if err := o.relay(events, publish); err != nil && err != errPublish {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+/*
+Error returned when the callback failed, the relay keeps going
+*/
+var errPublish = errors.New("could not publish an event")
+
+/*
+Publishes the pending events until there are none or the callback fails
+*/
+func (o *Outbox) relay(events *driver.Client, publish func(Event) error) error {
+	for {
+		cursor := events.FindMany(driver.F("state").Eq(StatePending),
+			options.Find().SetSort(driver.Sort().Asc("_id")).SetLimit(100))
+		if cursor == nil {
+			return errors.New("could not list the pending events")
+		}
+		var pending []Event
+		if err := cursor.All(context.Background(), &pending); err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		for _, event := range pending {
+			if err := publish(event); err != nil {
+				update := driver.Update().Inc("attempts", 1).Set("lastError", err.Error())
+				if err := events.FindOneAndUpdate(driver.F("_id").Eq(event.ID), update, nil).Err(); err != nil {
+					return err
+				}
+				return errPublish
+			}
+			update := driver.Update().Set("state", StatePublished).Set("publishedAt", time.Now())
+			if err := events.FindOneAndUpdate(driver.F("_id").Eq(event.ID), update, nil).Err(); err != nil {
+				return err
+			}
+		}
+	}
+}
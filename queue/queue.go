@@ -0,0 +1,325 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/olympsis/go-mongo/driver"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+States of a job
+*/
+const (
+	StatePending = "pending"
+	StateRunning = "running"
+	StateDone    = "done"
+	StateDead    = "dead"
+)
+
+/*
+Job object
+A job of the queue as it is stored in the collection
+*/
+type Job struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Queue       string             `bson:"queue"`
+	Payload     bson.Raw           `bson:"payload"`
+	Priority    int                `bson:"priority"`
+	State       string             `bson:"state"`
+	Attempts    int                `bson:"attempts"`
+	RunAt       time.Time          `bson:"runAt"`
+	LockedUntil time.Time          `bson:"lockedUntil"`
+	LastError   string             `bson:"lastError,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt"`
+}
+
+/*
+Options object
+Configures a queue
+
+	VisibilityTimeout: time a claimed job is hidden from other workers, it is claimed again once it expires. Defaults to 30 seconds
+
+	MaxAttempts: attempts after which a failing job is moved to the dead state. Defaults to 5
+
+	Backoff: delay before retrying a failed job after the given attempt. Defaults to exponential backoff starting at 1 second
+*/
+type Options struct {
+	VisibilityTimeout time.Duration
+	MaxAttempts       int
+	Backoff           func(attempt int) time.Duration
+}
+
+/*
+Queue object
+A persistent job queue stored in a collection, several queues can share a collection
+*/
+type Queue struct {
+	c    *driver.Client
+	name string
+	opts Options
+}
+
+/*
+Creates a queue on the collection set on the client
+
+	*driver.Client client with the collection of the jobs set
+
+	string: name of the queue
+
+	Options options of the queue
+
+Returns:
+
+	*Queue pointer to a queue
+*/
+func New(c *driver.Client, name string, opts Options) *Queue {
+	if opts.VisibilityTimeout == 0 {
+		opts.VisibilityTimeout = 30 * time.Second
+	}
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = func(attempt int) time.Duration {
+			return time.Second << (attempt - 1)
+		}
+	}
+	return &Queue{c: c, name: name, opts: opts}
+}
+
+/*
+Creates the index used to claim jobs
+
+Returns:
+
+	an err - error
+*/
+func (q *Queue) EnsureIndexes() error {
+	_, err := q.c.CreateIndex(bson.D{
+		{Key: "queue", Value: 1},
+		{Key: "state", Value: 1},
+		{Key: "priority", Value: -1},
+		{Key: "runAt", Value: 1},
+	}, nil)
+	return err
+}
+
+/*
+Adds a job to the queue
+
+	interface{} payload of the job
+
+	int priority of the job, jobs with a higher priority are claimed first
+
+	time.Duration delay before the job can be claimed
+
+Returns:
+
+	the id of the job - primitive.ObjectID
+
+	an err - error
+*/
+func (q *Queue) Enqueue(payload interface{}, priority int, delay time.Duration) (primitive.ObjectID, error) {
+	b, err := bson.Marshal(payload)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	now := time.Now()
+	job := Job{
+		ID:        primitive.NewObjectID(),
+		Queue:     q.name,
+		Payload:   b,
+		Priority:  priority,
+		State:     StatePending,
+		RunAt:     now.Add(delay),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := q.c.InsertOne(job, nil); err != nil {
+		return primitive.NilObjectID, err
+	}
+	return job.ID, nil
+}
+
+/*
+Claims the next job that is due, or whose previous claim expired
+The job is hidden from other workers for the visibility timeout, call
+Complete or Fail before it expires. Expired claims of jobs that used all
+of their attempts, ex: the worker crashed each time, move them to the dead state
+
+Returns:
+
+	the claimed job, nil if no job is due - *Job
+
+	an err - error
+*/
+func (q *Queue) Claim() (*Job, error) {
+	now := time.Now()
+	if err := q.bury(now); err != nil {
+		return nil, err
+	}
+	filter := driver.And(
+		driver.F("queue").Eq(q.name),
+		driver.Or(
+			driver.And(driver.F("state").Eq(StatePending), driver.F("runAt").Lte(now)),
+			driver.And(
+				driver.F("state").Eq(StateRunning),
+				driver.F("lockedUntil").Lte(now),
+				driver.F("attempts").Lt(q.opts.MaxAttempts),
+			),
+		),
+	)
+	update := driver.Update().
+		Set("state", StateRunning).
+		Set("lockedUntil", now.Add(q.opts.VisibilityTimeout)).
+		Set("updatedAt", now).
+		Inc("attempts", 1)
+	opts := options.FindOneAndUpdate().
+		SetSort(driver.Sort().Desc("priority").Asc("runAt")).
+		SetReturnDocument(options.After)
+
+	var job Job
+	err := q.c.FindOneAndUpdate(filter, update, opts).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+/*
+Moves the jobs whose last attempt expired without being settled to the dead state
+*/
+func (q *Queue) bury(now time.Time) error {
+	filter := driver.And(
+		driver.F("queue").Eq(q.name),
+		driver.F("state").Eq(StateRunning),
+		driver.F("lockedUntil").Lte(now),
+		driver.F("attempts").Gte(q.opts.MaxAttempts),
+	)
+	update := driver.Update().
+		Set("state", StateDead).
+		Set("lastError", "visibility timeout expired on the last attempt").
+		Set("updatedAt", now)
+	_, err := q.c.UpdateMany(filter, update, nil)
+	return err
+}
+
+/*
+Marks a claimed job as done
+
+	*Job job to complete
+
+Returns:
+
+	an err - error
+*/
+func (q *Queue) Complete(job *Job) error {
+	return q.settle(job, driver.Update().Set("state", StateDone).Set("updatedAt", time.Now()))
+}
+
+/*
+Marks a claimed job as failed
+It is retried after the backoff, or moved to the dead state once it used all of its attempts
+
+	*Job job that failed
+
+	error reason of the failure, can be nil
+
+Returns:
+
+	an err - error
+*/
+func (q *Queue) Fail(job *Job, reason error) error {
+	now := time.Now()
+	message := "failed"
+	if reason != nil {
+		message = reason.Error()
+	}
+	update := driver.Update().Set("lastError", message).Set("updatedAt", now)
+	if job.Attempts >= q.opts.MaxAttempts {
+		update.Set("state", StateDead)
+	} else {
+		update.Set("state", StatePending).Set("runAt", now.Add(q.opts.Backoff(job.Attempts)))
+	}
+	return q.settle(job, update)
+}
+
+/*
+Moves a dead job back to the pending state with its attempts reset
+
+	primitive.ObjectID id of the job
+
+Returns:
+
+	an err - error
+*/
+func (q *Queue) Retry(id primitive.ObjectID) error {
+	update := driver.Update().
+		Set("state", StatePending).
+		Set("attempts", 0).
+		Set("runAt", time.Now()).
+		Set("updatedAt", time.Now())
+	filter := driver.And(driver.F("_id").Eq(id), driver.F("state").Eq(StateDead))
+	return q.c.FindOneAndUpdate(filter, update, nil).Err()
+}
+
+/*
+Lists the dead jobs of the queue
+
+	int64 maximum number of jobs, 0 for no limit
+
+Returns:
+
+	the dead jobs - []Job
+
+	an err - error
+*/
+func (q *Queue) Dead(limit int64) ([]Job, error) {
+	cursor := q.c.FindMany(driver.And(driver.F("queue").Eq(q.name), driver.F("state").Eq(StateDead)),
+		options.Find().SetSort(driver.Sort().Asc("updatedAt")).SetLimit(limit))
+	if cursor == nil {
+		return nil, errors.New("could not list the dead jobs")
+	}
+	var jobs []Job
+	err := cursor.All(context.Background(), &jobs)
+	return jobs, err
+}
+
+/*
+Updates a job only if it is still held by the claim that returned it
+*/
+func (q *Queue) settle(job *Job, update *driver.UpdateBuilder) error {
+	filter := driver.And(
+		driver.F("_id").Eq(job.ID),
+		driver.F("state").Eq(StateRunning),
+		driver.F("attempts").Eq(job.Attempts),
+	)
+	err := q.c.FindOneAndUpdate(filter, update, nil).Err()
+	if err == mongo.ErrNoDocuments {
+		return errors.New("job was claimed again after its visibility timeout expired")
+	}
+	return err
+}
+
+/*
+Decodes the payload of the job
+
+	interface{} pointer to decode the payload into
+
+Returns:
+
+	an err - error
+*/
+func (j *Job) Decode(v interface{}) error {
+	return bson.Unmarshal(j.Payload, v)
+}
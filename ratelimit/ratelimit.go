@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/olympsis/go-mongo/driver"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Algorithms of a limiter
+*/
+const (
+	// counts the requests of fixed windows, bursts of up to twice the limit can happen around the start of a window
+	FixedWindow = "fixed"
+	// weighs the count of the previous window by how much of it overlaps the sliding window
+	SlidingWindow = "sliding"
+)
+
+/*
+Limiter object
+Limits the requests per key across every instance sharing the collection
+One document counts the requests of a key during a window and expires with a TTL index
+*/
+type Limiter struct {
+	c      *driver.Client
+	limit  int64
+	window time.Duration
+	mode   string
+}
+
+/*
+Creates a limiter on the collection set on the client
+
+	*driver.Client client with the collection of the counters set
+
+	int64 requests allowed per window
+
+	time.Duration length of the window
+
+	string: algorithm, FixedWindow or SlidingWindow
+
+Returns:
+
+	*Limiter pointer to a limiter
+
+	an err - error
+*/
+func New(c *driver.Client, limit int64, window time.Duration, mode string) (*Limiter, error) {
+	if _, err := c.ExpireAt("expiresAt"); err != nil {
+		return nil, err
+	}
+	return &Limiter{c: c, limit: limit, window: window, mode: mode}, nil
+}
+
+/*
+Counts a request for the key and checks if it is allowed
+Denied requests are counted too, so a client retrying too fast stays limited
+
+	string: key to limit. ex: an api key or an ip address
+
+Returns:
+
+	a boolean - bool
+
+	an err - error
+*/
+func (l *Limiter) Allow(key string) (bool, error) {
+	now := time.Now()
+	start := now.Truncate(l.window)
+	count, err := l.incr(key, start)
+	if err != nil {
+		return false, err
+	}
+	if l.mode != SlidingWindow {
+		return count <= l.limit, nil
+	}
+	previous, err := l.count(key, start.Add(-l.window))
+	if err != nil {
+		return false, err
+	}
+	overlap := 1 - float64(now.Sub(start))/float64(l.window)
+	return float64(previous)*overlap+float64(count) <= float64(l.limit), nil
+}
+
+/*
+Increments the counter of a window and returns its value
+*/
+func (l *Limiter) incr(key string, start time.Time) (int64, error) {
+	update := driver.Update().Inc("count", 1).SetOnInsert("expiresAt", start.Add(2*l.window))
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var counter struct {
+		Count int64 `bson:"count"`
+	}
+	err := l.c.FindOneAndUpdate(driver.F("_id").Eq(id(key, start)), update, opts).Decode(&counter)
+	if mongo.IsDuplicateKeyError(err) { // another instance created the counter at the same time
+		err = l.c.FindOneAndUpdate(driver.F("_id").Eq(id(key, start)), update, opts).Decode(&counter)
+	}
+	return counter.Count, err
+}
+
+/*
+Value of the counter of a window, 0 if there is none
+*/
+func (l *Limiter) count(key string, start time.Time) (int64, error) {
+	res := l.c.FindOne(driver.F("_id").Eq(id(key, start)))
+	if res == nil {
+		return 0, errors.New("could not connect to read the counter of " + key)
+	}
+	var counter struct {
+		Count int64 `bson:"count"`
+	}
+	err := res.Decode(&counter)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	return counter.Count, err
+}
+
+func id(key string, start time.Time) string {
+	return key + ":" + strconv.FormatInt(start.Unix(), 10)
+}
@@ -0,0 +1,166 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+/*
+AWSSecretsManager object
+Reads the credentials from a JSON secret of AWS Secrets Manager. ex: {"username": "api", "password": "..."}
+
+	Region: region of the secret. ex: "eu-west-1"
+
+	SecretID: name or ARN of the secret
+
+	AccessKeyID: access key to sign the request with, AWS_ACCESS_KEY_ID when empty
+
+	SecretAccessKey: secret key to sign the request with, AWS_SECRET_ACCESS_KEY when empty
+
+	SessionToken: token of temporary credentials, AWS_SESSION_TOKEN when empty
+
+	UsernameKey: key of the username in the secret, "username" when empty
+
+	PasswordKey: key of the password in the secret, "password" when empty
+
+	HTTPClient: client to call AWS with, http.DefaultClient when nil
+*/
+type AWSSecretsManager struct {
+	Region          string
+	SecretID        string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	UsernameKey     string
+	PasswordKey     string
+	HTTPClient      *http.Client
+}
+
+/*
+Reads the current version of the secret
+
+	context.Context context of the request
+
+Returns:
+
+	the username - string
+
+	the password - string
+
+	an err - error
+*/
+func (a *AWSSecretsManager) Credentials(ctx context.Context) (string, string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": a.SecretID})
+	if err != nil {
+		return "", "", err
+	}
+	host := "secretsmanager." + a.Region + ".amazonaws.com"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+host+"/", bytes.NewReader(body))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if err := a.sign(req, host, body, time.Now().UTC()); err != nil {
+		return "", "", err
+	}
+	res, err := httpClient(a.HTTPClient).Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", "", errors.New("secrets manager returned " + res.Status + ": " + string(b))
+	}
+	var value struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return "", "", err
+	}
+	var secret map[string]string
+	if err := json.Unmarshal([]byte(value.SecretString), &secret); err != nil {
+		return "", "", errors.New("secret " + a.SecretID + " is not a JSON object")
+	}
+	return fields(secret, a.UsernameKey, a.PasswordKey)
+}
+
+/*
+Signs the request with AWS Signature Version 4
+*/
+func (a *AWSSecretsManager) sign(req *http.Request, host string, body []byte, now time.Time) error {
+	accessKey := first(a.AccessKeyID, os.Getenv("AWS_ACCESS_KEY_ID"))
+	secretKey := first(a.SecretAccessKey, os.Getenv("AWS_SECRET_ACCESS_KEY"))
+	token := first(a.SessionToken, os.Getenv("AWS_SESSION_TOKEN"))
+	if accessKey == "" || secretKey == "" {
+		return errors.New("no AWS credentials to sign the request with")
+	}
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+
+	// headers must be sorted by name
+	names := []string{"content-type", "host", "x-amz-date"}
+	if token != "" {
+		names = append(names, "x-amz-security-token")
+	}
+	names = append(names, "x-amz-target")
+	var canonical strings.Builder
+	for _, name := range names {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = host
+		}
+		canonical.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signed := strings.Join(names, ";")
+	request := strings.Join([]string{"POST", "/", "", canonical.String(), signed, hashHex(body)}, "\n")
+
+	scope := date + "/" + a.Region + "/secretsmanager/aws4_request"
+	toSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hashHex([]byte(request))}, "\n")
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, a.Region)
+	key = hmacSHA256(key, "secretsmanager")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+", SignedHeaders="+signed+", Signature="+signature)
+	return nil
+}
+
+func hashHex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+/*
+Vault object
+Reads the credentials from a HashiCorp Vault KV version 2 secret
+
+	Address: address of the Vault server. ex: "https://vault.internal:8200"
+
+	Token: token to read the secret with
+
+	Mount: mount path of the KV engine, "secret" when empty
+
+	Path: path of the secret in the engine. ex: "services/api/mongo"
+
+	UsernameKey: key of the username in the secret, "username" when empty
+
+	PasswordKey: key of the password in the secret, "password" when empty
+
+	HTTPClient: client to call Vault with, http.DefaultClient when nil
+*/
+type Vault struct {
+	Address     string
+	Token       string
+	Mount       string
+	Path        string
+	UsernameKey string
+	PasswordKey string
+	HTTPClient  *http.Client
+}
+
+/*
+Reads the latest version of the secret
+
+	context.Context context of the request
+
+Returns:
+
+	the username - string
+
+	the password - string
+
+	an err - error
+*/
+func (v *Vault) Credentials(ctx context.Context) (string, string, error) {
+	mount := v.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+	url := strings.TrimSuffix(v.Address, "/") + "/v1/" + strings.Trim(mount, "/") + "/data/" + strings.Trim(v.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+	res, err := httpClient(v.HTTPClient).Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", "", errors.New("vault returned " + res.Status + " reading " + v.Path)
+	}
+	var body struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", "", err
+	}
+	return fields(body.Data.Data, v.UsernameKey, v.PasswordKey)
+}
+
+/*
+Username and password of a secret
+*/
+func fields(secret map[string]string, usernameKey string, passwordKey string) (string, string, error) {
+	if usernameKey == "" {
+		usernameKey = "username"
+	}
+	if passwordKey == "" {
+		passwordKey = "password"
+	}
+	username, ok := secret[usernameKey]
+	if !ok {
+		return "", "", errors.New("secret has no " + usernameKey)
+	}
+	password, ok := secret[passwordKey]
+	if !ok {
+		return "", "", errors.New("secret has no " + passwordKey)
+	}
+	return username, password, nil
+}
+
+func httpClient(c *http.Client) *http.Client {
+	if c == nil {
+		return http.DefaultClient
+	}
+	return c
+}
@@ -0,0 +1,183 @@
+package sessions
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/olympsis/go-mongo/driver"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+/*
+Session object
+An HTTP session as it is stored in the collection
+*/
+type Session struct {
+	ID        string                 `bson:"_id"`
+	Values    map[string]interface{} `bson:"values"`
+	ExpiresAt time.Time              `bson:"expiresAt"`
+	IsNew     bool                   `bson:"-"`
+}
+
+/*
+Options object
+Configures a store
+
+	TTL: time a session lives without being used. Defaults to 24 hours
+
+	Sliding: renews the session every time it is loaded, so it only expires after TTL of inactivity
+
+	Cookie: template of the session cookie, its Name defaults to "session" and its Path to "/"
+*/
+type Options struct {
+	TTL     time.Duration
+	Sliding bool
+	Cookie  http.Cookie
+}
+
+/*
+Store object
+Keeps HTTP sessions in a collection, the session id is kept in a cookie
+*/
+type Store struct {
+	c    *driver.Client
+	opts Options
+}
+
+/*
+Creates a store on the collection set on the client
+Expired sessions are removed by a TTL index
+
+	*driver.Client client with the collection of the sessions set
+
+	Options options of the store
+
+Returns:
+
+	*Store pointer to a store
+
+	an err - error
+*/
+func New(c *driver.Client, opts Options) (*Store, error) {
+	if opts.TTL == 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.Cookie.Name == "" {
+		opts.Cookie.Name = "session"
+	}
+	if opts.Cookie.Path == "" {
+		opts.Cookie.Path = "/"
+	}
+	if _, err := c.ExpireAt("expiresAt"); err != nil {
+		return nil, err
+	}
+	return &Store{c: c, opts: opts}, nil
+}
+
+/*
+Gets the session of a request
+A new session is returned when the request has no session or it expired
+
+	*http.Request request to get the session of
+
+Returns:
+
+	*Session pointer to the session
+
+	an err - error
+*/
+func (s *Store) Get(r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(s.opts.Cookie.Name)
+	if err != nil {
+		return s.new()
+	}
+	sess, err := s.load(cookie.Value)
+	if err == mongo.ErrNoDocuments {
+		return s.new()
+	}
+	return sess, err
+}
+
+/*
+Saves the session and sets its cookie on the response
+Call it before writing the body of the response
+
+	http.ResponseWriter response to set the cookie on
+
+	*Session session to save
+
+Returns:
+
+	an err - error
+*/
+func (s *Store) Save(w http.ResponseWriter, sess *Session) error {
+	sess.ExpiresAt = time.Now().Add(s.opts.TTL)
+	if _, err := s.c.Upsert(driver.F("_id").Eq(sess.ID), sess); err != nil {
+		return err
+	}
+	sess.IsNew = false
+	cookie := s.opts.Cookie
+	cookie.Value = sess.ID
+	cookie.Expires = sess.ExpiresAt
+	http.SetCookie(w, &cookie)
+	return nil
+}
+
+/*
+Deletes the session and clears its cookie
+
+	http.ResponseWriter response to clear the cookie on
+
+	*Session session to delete
+
+Returns:
+
+	an err - error
+*/
+func (s *Store) Destroy(w http.ResponseWriter, sess *Session) error {
+	if _, err := s.c.RemoveOne(driver.F("_id").Eq(sess.ID), nil); err != nil {
+		return err
+	}
+	cookie := s.opts.Cookie
+	cookie.MaxAge = -1
+	http.SetCookie(w, &cookie)
+	return nil
+}
+
+/*
+Loads a session that has not expired, renewing it when the store is sliding
+*/
+func (s *Store) load(id string) (*Session, error) {
+	now := time.Now()
+	filter := driver.And(driver.F("_id").Eq(id), driver.F("expiresAt").Gt(now))
+	var res *mongo.SingleResult
+	if s.opts.Sliding {
+		res = s.c.FindOneAndUpdate(filter, driver.Update().Set("expiresAt", now.Add(s.opts.TTL)),
+			options.FindOneAndUpdate().SetReturnDocument(options.After))
+	} else {
+		res = s.c.FindOne(filter)
+	}
+	if res == nil {
+		return nil, errors.New("could not connect to load the session")
+	}
+	var sess Session
+	if err := res.Decode(&sess); err != nil {
+		return nil, err
+	}
+	return &sess, nil
+}
+
+/*
+Creates a session with a random id
+*/
+func (s *Store) new() (*Session, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return &Session{ID: base64.RawURLEncoding.EncodeToString(b), Values: map[string]interface{}{}, IsNew: true}, nil
+}